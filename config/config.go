@@ -3,23 +3,106 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"sort"
 
 	"github.com/cloudwego/eino/schema"
 	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
-	APIKey    string        `yaml:"apikey"`
-	BaseURL   string        `yaml:"base_url"`
-	Summary   SummaryConfig `yaml:"summary"`
-	ChatAgent ChatAgent     `yaml:"chatagent"`
+	APIKey        string              `yaml:"apikey"`
+	BaseURL       string              `yaml:"base_url"`
+	MaxUploadMB   int                 `yaml:"max_upload_mb"`
+	Summary       SummaryConfig       `yaml:"summary"`
+	ChatAgent     ChatAgent           `yaml:"chatagent"`
+	Transcription TranscriptionConfig `yaml:"transcription"`
+	Live          LiveConfig          `yaml:"live"`
+	Redaction     RedactionConfig     `yaml:"redaction"`
+	Normalization NormalizationConfig `yaml:"normalization"`
+	Jobs          JobsConfig          `yaml:"jobs"`
+}
+
+// JobsConfig configures the background job queue that transcribes and summarizes meetings
+type JobsConfig struct {
+	Workers     int `yaml:"workers"`
+	MaxAttempts int `yaml:"max_attempts"` // Runs of a job before it is marked as failed
+	// RetryBackoffSeconds is the delay before the first retry; it doubles with every further attempt
+	RetryBackoffSeconds int `yaml:"retry_backoff_seconds"`
+}
+
+// NormalizationConfig configures the transcript clean-up applied before summarization.
+// Meetings can override these settings individually.
+type NormalizationConfig struct {
+	Enabled       bool                `yaml:"enabled"`
+	MergeAdjacent bool                `yaml:"merge_adjacent"`  // Merge consecutive utterances of the same speaker
+	MaxGapSeconds int                 `yaml:"max_gap_seconds"` // Longest pause bridged when merging; 0 means no limit
+	StripFillers  bool                `yaml:"strip_fillers"`
+	Fillers       []string            `yaml:"fillers"` // Built-in Chinese and English fillers are used if empty
+	Aliases       map[string][]string `yaml:"aliases"` // Canonical speaker name to alternative spellings
+}
+
+// RedactionConfig configures the replacement of personal information before model calls
+type RedactionConfig struct {
+	Enabled       bool            `yaml:"enabled"`
+	RestoreOutput bool            `yaml:"restore_output"` // Map placeholders back in summaries and chat replies
+	Rules         []RedactionRule `yaml:"rules"`          // Built-in rules for emails, phone and ID numbers are used if empty
+	Allow         []string        `yaml:"allow"`          // Values that are never redacted
+	Deny          []string        `yaml:"deny"`           // Terms that are always redacted
+}
+
+// RedactionRule is a named regular expression; matches become "[NAME_n]" placeholders
+type RedactionRule struct {
+	Name    string `yaml:"name"`
+	Pattern string `yaml:"pattern"`
+}
+
+// LiveConfig configures live meetings
+type LiveConfig struct {
+	// SummaryIntervalSeconds is how often a rolling summary is pushed to WebSocket clients; negative disables it
+	SummaryIntervalSeconds int `yaml:"summary_interval_seconds"`
+}
+
+// TranscriptionConfig configures the local command used to transcribe audio uploads
+type TranscriptionConfig struct {
+	Command        string   `yaml:"command"` // Leave empty to disable audio uploads
+	Args           []string `yaml:"args"`    // "{input}" and "{output}" are replaced with the audio and transcript paths
+	OutputFormat   string   `yaml:"output_format"`
+	AudioDir       string   `yaml:"audio_dir"`
+	TimeoutSeconds int      `yaml:"timeout_seconds"`
 }
 
 type SummaryConfig struct {
 	Model         string `yaml:"model"`
 	SystemMessage string `yaml:"system_message"`
+	// MergeMessage is the system message for merging the partial summaries of a long transcript; a built-in one is used if empty
+	MergeMessage string `yaml:"merge_message"`
+	// TokenBudgets is the most transcript tokens sent to a model in one request, by model name; "default" applies to unlisted models
+	TokenBudgets map[string]int `yaml:"token_budgets"`
+	// MaxRepairAttempts is how often a reply that isn't valid summary JSON is sent back with the error; negative disables it
+	MaxRepairAttempts int `yaml:"max_repair_attempts"`
+	// ChapterMinMinutes is how long a meeting must be to be split into chapters; negative disables chapters
+	ChapterMinMinutes int `yaml:"chapter_min_minutes"`
+	// Templates are named system messages that replace SystemMessage when a summary is regenerated with them.
+	// The built-in standup, retro, interview and design_review templates can be overridden here.
+	Templates map[string]string `yaml:"templates"`
 }
 
+// defaultSummaryTemplates are the summary templates available without configuration
+var defaultSummaryTemplates = map[string]string{
+	"standup": `你是一名会议记录员，正在整理一场站会。请按参会人概括每个人已完成的工作、接下来的计划和遇到的阻碍，
+阻碍同时列入 risks，接下来的计划列入 tasks 并注明负责人。`,
+	"retro": `你是一名会议记录员，正在整理一场回顾会。请在总结中分别概括做得好的地方、需要改进的地方，
+把约定的改进措施列入 tasks 并注明负责人，把反复出现的问题列入 risks。`,
+	"interview": `你是一名面试记录员，正在整理一场面试。请在总结中概括候选人的背景、考察的问题和候选人的回答要点，
+以及表现出的优势和不足；面试官给出的结论列入 decisions，后续安排列入 tasks。`,
+	"design_review": `你是一名会议记录员，正在整理一场设计评审。请在总结中概括评审的方案及其背景，
+把被采纳或否决的方案及理由列入 decisions，把评审意见中需要修改的内容列入 tasks 并注明负责人，
+把未解决的问题和技术风险分别列入 open_questions 和 risks。`,
+}
+
+// defaultSummaryTokenBudget is used for models without a configured token budget
+const defaultSummaryTokenBudget = 24000
+
 type ChatAgent struct {
 	Model          string          `yaml:"model"`
 	SystemMessage  string          `yaml:"system_message"`
@@ -51,6 +134,42 @@ func LoadConfig(configPath string) (*Config, error) {
 		return nil, fmt.Errorf("error parsing config file: %v", err)
 	}
 
+	if config.MaxUploadMB <= 0 {
+		config.MaxUploadMB = 200
+	}
+	if config.Transcription.AudioDir == "" {
+		config.Transcription.AudioDir = "uploads/audio"
+	}
+	if config.Live.SummaryIntervalSeconds == 0 {
+		config.Live.SummaryIntervalSeconds = 60
+	}
+	if config.Summary.MaxRepairAttempts == 0 {
+		config.Summary.MaxRepairAttempts = 2
+	}
+	if config.Summary.ChapterMinMinutes == 0 {
+		config.Summary.ChapterMinMinutes = 10
+	}
+	if config.Summary.Templates == nil {
+		config.Summary.Templates = make(map[string]string)
+	}
+	for name, message := range defaultSummaryTemplates {
+		if _, ok := config.Summary.Templates[name]; !ok {
+			config.Summary.Templates[name] = message
+		}
+	}
+	if config.Jobs.Workers <= 0 {
+		config.Jobs.Workers = 2
+	}
+	if config.Jobs.MaxAttempts <= 0 {
+		config.Jobs.MaxAttempts = 3
+	}
+	if config.Jobs.RetryBackoffSeconds <= 0 {
+		config.Jobs.RetryBackoffSeconds = 30
+	}
+	if config.Transcription.OutputFormat == "" {
+		config.Transcription.OutputFormat = "json"
+	}
+
 	AppConfig = &config
 	return &config, nil
 }
@@ -63,6 +182,43 @@ func (c *Config) GetSummarySystemMessage() *schema.Message {
 	}
 }
 
+// GetSummaryTemplateMessage returns the system message of a named summary template;
+// an empty name selects the default system message
+func (c *Config) GetSummaryTemplateMessage(name string) (*schema.Message, bool) {
+	if name == "" {
+		return c.GetSummarySystemMessage(), true
+	}
+	content, ok := c.Summary.Templates[name]
+	if !ok {
+		return nil, false
+	}
+	return &schema.Message{
+		Role:    schema.System,
+		Content: content,
+	}, true
+}
+
+// SummaryTemplateNames returns the names of the configured summary templates in alphabetical order
+func (c *Config) SummaryTemplateNames() []string {
+	names := make([]string, 0, len(c.Summary.Templates))
+	for name := range c.Summary.Templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SummaryTokenBudget returns the most transcript tokens the summary model is sent in one request
+func (c *Config) SummaryTokenBudget() int {
+	if budget := c.Summary.TokenBudgets[c.Summary.Model]; budget > 0 {
+		return budget
+	}
+	if budget := c.Summary.TokenBudgets["default"]; budget > 0 {
+		return budget
+	}
+	return defaultSummaryTokenBudget
+}
+
 // GetChatAgentSystemMessage returns the system message for the chat agent as a properly formatted schema.Message
 func (c *Config) GetChatAgentSystemMessage() *schema.Message {
 	return &schema.Message{
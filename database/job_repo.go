@@ -0,0 +1,170 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"meetingagent/models"
+)
+
+// Job times are stored in UTC so that run_at compares correctly as text
+const jobColumns = `id, kind, meeting_id, status, attempts, max_attempts, last_error, run_at, created_at, updated_at`
+
+func scanJob(row rowScanner) (*models.Job, error) {
+	var j models.Job
+	err := row.Scan(&j.ID, &j.Kind, &j.MeetingID, &j.Status, &j.Attempts, &j.MaxAttempts,
+		&j.LastError, &j.RunAt, &j.CreatedAt, &j.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// EnqueueJob inserts a queued job. A job of the same kind that is still queued for
+// the meeting already covers the new one, so its ID is returned instead. The unique
+// index on queued jobs makes this safe against concurrent enqueues.
+func (r *SQLiteRepository) EnqueueJob(job *models.Job) (int64, error) {
+	now := time.Now().UTC()
+	if job.RunAt.IsZero() {
+		job.RunAt = now
+	}
+	job.Status = models.JobQueued
+	job.CreatedAt, job.UpdatedAt = now, now
+	for {
+		err := r.db.QueryRow(`
+INSERT INTO jobs (kind, meeting_id, status, attempts, max_attempts, run_at, created_at, updated_at)
+VALUES (?, ?, ?, 0, ?, ?, ?, ?)
+ON CONFLICT (kind, meeting_id) WHERE status = 'queued' DO NOTHING
+RETURNING id;
+`, job.Kind, job.MeetingID, job.Status, job.MaxAttempts, job.RunAt.UTC(), job.CreatedAt, job.UpdatedAt).Scan(&job.ID)
+		if err == nil {
+			return job.ID, nil
+		}
+		if err != sql.ErrNoRows {
+			return 0, fmt.Errorf("failed to insert job: %w", err)
+		}
+
+		err = r.db.QueryRow(`SELECT id FROM jobs WHERE kind = ? AND meeting_id = ? AND status = 'queued';`,
+			job.Kind, job.MeetingID).Scan(&job.ID)
+		if err == nil {
+			return job.ID, nil
+		}
+		// The queued job was claimed in between; try inserting again
+		if err != sql.ErrNoRows {
+			return 0, fmt.Errorf("failed to query queued job: %w", err)
+		}
+	}
+}
+
+// ClaimJob atomically moves the next due job from queued to running. A job waits while
+// the meeting has a running job of the same kind, so that two runs can't overlap.
+func (r *SQLiteRepository) ClaimJob(now time.Time) (*models.Job, error) {
+	now = now.UTC()
+	row := r.db.QueryRow(`
+UPDATE jobs
+SET status = 'running', attempts = attempts + 1, updated_at = ?
+WHERE id = (
+	SELECT id FROM jobs AS queued
+	WHERE status = 'queued' AND run_at <= ?
+	  AND meeting_id NOT IN (SELECT meeting_id FROM jobs WHERE status = 'running' AND kind = queued.kind)
+	ORDER BY run_at, id LIMIT 1
+)
+RETURNING `+jobColumns+`;
+`, now, now)
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim job: %w", err)
+	}
+	return job, nil
+}
+
+// CompleteJob marks a running job as succeeded
+func (r *SQLiteRepository) CompleteJob(id int64) error {
+	return r.finishJob(id, models.JobSucceeded, "")
+}
+
+// FailJob marks a job as failed for good
+func (r *SQLiteRepository) FailJob(id int64, errMsg string) error {
+	return r.finishJob(id, models.JobFailed, errMsg)
+}
+
+func (r *SQLiteRepository) finishJob(id int64, status string, errMsg string) error {
+	_, err := r.db.Exec(`UPDATE jobs SET status = ?, last_error = ?, updated_at = ? WHERE id = ?;`,
+		status, sql.NullString{String: errMsg, Valid: errMsg != ""}, time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to update job: %w", err)
+	}
+	return nil
+}
+
+// RetryJob queues a failed job again to be started at runAt
+func (r *SQLiteRepository) RetryJob(id int64, runAt time.Time, errMsg string) error {
+	_, err := r.db.Exec(`UPDATE jobs SET status = 'queued', last_error = ?, run_at = ?, updated_at = ? WHERE id = ?;`,
+		errMsg, runAt.UTC(), time.Now().UTC(), id)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule job: %w", err)
+	}
+	return nil
+}
+
+// TouchJob records that a running job is still being worked on
+func (r *SQLiteRepository) TouchJob(id int64) error {
+	if _, err := r.db.Exec(`UPDATE jobs SET updated_at = ? WHERE id = ? AND status = 'running';`, time.Now().UTC(), id); err != nil {
+		return fmt.Errorf("failed to touch job: %w", err)
+	}
+	return nil
+}
+
+// RequeueRunningJobs queues the running jobs not touched since staleBefore, whose process
+// stopped without finishing them. Their interrupted run still counts as an attempt.
+func (r *SQLiteRepository) RequeueRunningJobs(staleBefore time.Time) ([]models.Job, error) {
+	rows, err := r.db.Query(`
+UPDATE jobs
+SET status = 'queued', last_error = 'interrupted by a restart', updated_at = ?
+WHERE status = 'running' AND updated_at < ?
+RETURNING `+jobColumns+`;
+`, time.Now().UTC(), staleBefore.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to requeue running jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []models.Job
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan job row: %w", err)
+		}
+		jobs = append(jobs, *job)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating job rows: %w", err)
+	}
+	return jobs, nil
+}
+
+// NextJobRunAt returns the run time of the earliest queued job
+func (r *SQLiteRepository) NextJobRunAt() (*time.Time, error) {
+	var runAt time.Time
+	err := r.db.QueryRow(`SELECT run_at FROM jobs WHERE status = 'queued' ORDER BY run_at LIMIT 1;`).Scan(&runAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query next job: %w", err)
+	}
+	return &runAt, nil
+}
+
+// CountPendingJobs counts the jobs that are queued or running
+func (r *SQLiteRepository) CountPendingJobs() (int, error) {
+	var n int
+	if err := r.db.QueryRow(`SELECT COUNT(*) FROM jobs WHERE status IN ('queued', 'running');`).Scan(&n); err != nil {
+		return 0, fmt.Errorf("failed to count pending jobs: %w", err)
+	}
+	return n, nil
+}
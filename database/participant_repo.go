@@ -0,0 +1,212 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"meetingagent/models"
+)
+
+const participantColumns = `id, display_name, email, aliases_json, created_at, modified_at`
+
+func scanParticipant(row rowScanner) (*models.Participant, error) {
+	var p models.Participant
+	err := row.Scan(&p.ID, &p.DisplayName, &p.Email, &p.AliasesJSON, &p.CreatedAt, &p.ModifiedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CreateParticipant inserts a new participant record into the database.
+func (r *SQLiteRepository) CreateParticipant(participant *models.Participant) (int64, error) {
+	now := time.Now()
+	participant.CreatedAt, participant.ModifiedAt = now, now
+	result, err := r.db.Exec(`
+INSERT INTO participants (display_name, email, aliases_json, created_at, modified_at)
+VALUES (?, ?, ?, ?, ?);
+`, participant.DisplayName, participant.Email, participant.AliasesJSON, participant.CreatedAt, participant.ModifiedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert participant: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+	return id, nil
+}
+
+// ListParticipants retrieves all participants ordered by name
+func (r *SQLiteRepository) ListParticipants() ([]models.Participant, error) {
+	rows, err := r.db.Query(`SELECT ` + participantColumns + ` FROM participants ORDER BY display_name;`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query participants: %w", err)
+	}
+	defer rows.Close()
+
+	var participants []models.Participant
+	for rows.Next() {
+		p, err := scanParticipant(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan participant row: %w", err)
+		}
+		participants = append(participants, *p)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating participant rows: %w", err)
+	}
+	return participants, nil
+}
+
+func (r *SQLiteRepository) GetParticipantByID(id int64) (*models.Participant, error) {
+	p, err := scanParticipant(r.db.QueryRow(`SELECT `+participantColumns+` FROM participants WHERE id = ?;`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to query participant by ID: %w", err)
+	}
+	return p, nil
+}
+
+func (r *SQLiteRepository) UpdateParticipant(id int64, participant *models.Participant) error {
+	participant.ModifiedAt = time.Now()
+	_, err := r.db.Exec(`
+UPDATE participants
+SET display_name = ?, email = ?, aliases_json = ?, modified_at = ?
+WHERE id = ?;
+`, participant.DisplayName, participant.Email, participant.AliasesJSON, participant.ModifiedAt, id)
+	if err != nil {
+		return fmt.Errorf("failed to update participant: %w", err)
+	}
+	return nil
+}
+
+// ListMeetingSpeakers retrieves the speaker links of a meeting
+func (r *SQLiteRepository) ListMeetingSpeakers(meetingID int64) ([]models.MeetingSpeaker, error) {
+	rows, err := r.db.Query(`
+SELECT meeting_id, speaker, participant_id, manual
+FROM meeting_speakers
+WHERE meeting_id = ?
+ORDER BY speaker;
+`, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query meeting speakers: %w", err)
+	}
+	defer rows.Close()
+
+	var links []models.MeetingSpeaker
+	for rows.Next() {
+		var link models.MeetingSpeaker
+		if err := rows.Scan(&link.MeetingID, &link.Speaker, &link.ParticipantID, &link.Manual); err != nil {
+			return nil, fmt.Errorf("failed to scan meeting speaker row: %w", err)
+		}
+		links = append(links, link)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating meeting speaker rows: %w", err)
+	}
+	return links, nil
+}
+
+// SetMeetingSpeaker stores a manual speaker link, replacing any existing link for the speaker
+func (r *SQLiteRepository) SetMeetingSpeaker(link models.MeetingSpeaker) error {
+	_, err := r.db.Exec(`
+INSERT OR REPLACE INTO meeting_speakers (meeting_id, speaker, participant_id, manual)
+VALUES (?, ?, ?, 1);
+`, link.MeetingID, link.Speaker, link.ParticipantID)
+	if err != nil {
+		return fmt.Errorf("failed to set meeting speaker: %w", err)
+	}
+	return nil
+}
+
+// ReplaceMatchedSpeakers replaces the links of a meeting that were matched by
+// name. Manual links are kept and take precedence over the given ones.
+func (r *SQLiteRepository) ReplaceMatchedSpeakers(meetingID int64, links []models.MeetingSpeaker) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM meeting_speakers WHERE meeting_id = ? AND manual = 0;`, meetingID); err != nil {
+		return fmt.Errorf("failed to clear matched speakers: %w", err)
+	}
+	for _, link := range links {
+		if _, err := tx.Exec(`
+INSERT OR IGNORE INTO meeting_speakers (meeting_id, speaker, participant_id, manual)
+VALUES (?, ?, ?, 0);
+`, meetingID, link.Speaker, link.ParticipantID); err != nil {
+			return fmt.Errorf("failed to insert meeting speaker %q: %w", link.Speaker, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit meeting speakers: %w", err)
+	}
+	return nil
+}
+
+// ListMeetingsByParticipant retrieves the meetings in which a participant spoke
+func (r *SQLiteRepository) ListMeetingsByParticipant(participantID int64) ([]models.Meeting, error) {
+	query := `
+SELECT ` + meetingColumns + `
+FROM meetings
+WHERE deleted_at IS NULL
+  AND id IN (SELECT meeting_id FROM meeting_speakers WHERE participant_id = ?)
+ORDER BY uploaded_at DESC;
+`
+	rows, err := r.db.Query(query, participantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query meetings by participant: %w", err)
+	}
+	defer rows.Close()
+
+	var meetings []models.Meeting
+	for rows.Next() {
+		m, err := scanMeeting(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan meeting row: %w", err)
+		}
+		meetings = append(meetings, *m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating meeting rows: %w", err)
+	}
+	return meetings, nil
+}
+
+// ListSpeakerMeetingIDs retrieves the meetings in which one of the names spoke, ignoring
+// case, or that have a speaker linked to the participant
+func (r *SQLiteRepository) ListSpeakerMeetingIDs(participantID int64, names []string) ([]int64, error) {
+	query := `SELECT meeting_id FROM meeting_speakers WHERE participant_id = ?`
+	args := []any{participantID}
+	if len(names) > 0 {
+		query += `
+UNION
+SELECT DISTINCT meeting_id FROM utterances WHERE LOWER(TRIM(speaker)) IN (?` + strings.Repeat(`, ?`, len(names)-1) + `)`
+		for _, name := range names {
+			args = append(args, strings.ToLower(strings.TrimSpace(name)))
+		}
+	}
+	rows, err := r.db.Query(query+`;`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query speaker meetings: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan meeting ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating meeting IDs: %w", err)
+	}
+	return ids, nil
+}
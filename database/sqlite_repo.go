@@ -19,13 +19,76 @@ func NewSQLiteRepository(db *sql.DB) *SQLiteRepository {
 	return &SQLiteRepository{db: db}
 }
 
+// meetingColumns lists the meetings columns in the order scanMeeting expects them
+const meetingColumns = `id, name, transcript, summary_text, tasks_json, tasks_status_num,
+	   chat_history, remark, audio_filename, participants_json, title, description, scheduled_at,
+	   content_hash, audio_path, live, redact_allow_json, redact_deny_json,
+	   normalization_json, normalized_utterances_json, sections_json, summary_options_json, chapters_json, citations_json, task_items_json,
+	   summary_status, summary_error, summary_attempts, summary_version, uploaded_at, modified_at, deleted_at`
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanMeeting(row rowScanner) (*models.Meeting, error) {
+	var m models.Meeting
+	err := row.Scan(
+		&m.ID,
+		&m.Name,
+		&m.Transcript,
+		&m.SummaryText,
+		&m.TasksJSON,
+		&m.TasksStatusNum,
+		&m.ChatHistory,
+		&m.Remark,
+		&m.AudioFilename,
+		&m.ParticipantsJSON,
+		&m.Title,
+		&m.Description,
+		&m.ScheduledAt,
+		&m.ContentHash,
+		&m.AudioPath,
+		&m.Live,
+		&m.RedactAllowJSON,
+		&m.RedactDenyJSON,
+		&m.NormalizationJSON,
+		&m.NormalizedUtterancesJSON,
+		&m.SectionsJSON,
+		&m.SummaryOptionsJSON,
+		&m.ChaptersJSON,
+		&m.CitationsJSON,
+		&m.TaskItemsJSON,
+		&m.SummaryStatus,
+		&m.SummaryError,
+		&m.SummaryAttempts,
+		&m.CurrentSummaryVersion,
+		&m.UploadedAt,
+		&m.ModifiedAt,
+		&m.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
 // CreateMeeting inserts a new meeting record into the database.
 func (r *SQLiteRepository) CreateMeeting(meeting *models.Meeting) (int64, error) {
+	return r.CreateMeetingWithUtterances(meeting, nil)
+}
+
+// CreateMeetingWithUtterances inserts a new meeting record together with its utterances,
+// atomically, so that a failure leaves no meeting behind.
+func (r *SQLiteRepository) CreateMeetingWithUtterances(meeting *models.Meeting, utterances []models.Utterance) (int64, error) {
 	query := `
 INSERT INTO meetings (
 	   name, transcript, summary_text, tasks_json, tasks_status_num,
-	   chat_history, remark, audio_filename, uploaded_at, modified_at, deleted_at
-) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	   chat_history, remark, audio_filename, participants_json, title, description, scheduled_at,
+	   content_hash, audio_path, live, redact_allow_json, redact_deny_json,
+	   normalization_json, normalized_utterances_json, sections_json, summary_options_json, chapters_json, citations_json, task_items_json,
+	   summary_status, summary_error, summary_attempts, uploaded_at, modified_at, deleted_at
+) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
 `
 	// Ensure timestamps are set if not already
 	if meeting.UploadedAt.IsZero() {
@@ -35,7 +98,13 @@ INSERT INTO meetings (
 		meeting.ModifiedAt = time.Now()
 	}
 
-	result, err := r.db.Exec(query,
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(query,
 		meeting.Name,
 		meeting.Transcript,
 		meeting.SummaryText,
@@ -44,6 +113,25 @@ INSERT INTO meetings (
 		meeting.ChatHistory,
 		meeting.Remark,
 		meeting.AudioFilename,
+		meeting.ParticipantsJSON,
+		meeting.Title,
+		meeting.Description,
+		meeting.ScheduledAt,
+		meeting.ContentHash,
+		meeting.AudioPath,
+		meeting.Live,
+		meeting.RedactAllowJSON,
+		meeting.RedactDenyJSON,
+		meeting.NormalizationJSON,
+		meeting.NormalizedUtterancesJSON,
+		meeting.SectionsJSON,
+		meeting.SummaryOptionsJSON,
+		meeting.ChaptersJSON,
+		meeting.CitationsJSON,
+		meeting.TaskItemsJSON,
+		meeting.SummaryStatus,
+		meeting.SummaryError,
+		meeting.SummaryAttempts,
 		meeting.UploadedAt,
 		meeting.ModifiedAt,
 		meeting.DeletedAt,
@@ -57,15 +145,20 @@ INSERT INTO meetings (
 	if err != nil {
 		return 0, fmt.Errorf("failed to get last insert ID: %w", err)
 	}
+	if err := insertUtterances(tx, id, utterances); err != nil {
+		return 0, err
+	}
 
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 	return id, nil
 }
 
 // ListMeetings retrieves all meetings that haven't been deleted
 func (r *SQLiteRepository) ListMeetings() ([]models.Meeting, error) {
 	query := `
-SELECT id, name, transcript, summary_text, tasks_json, tasks_status_num,
-	      chat_history, remark, audio_filename, uploaded_at, modified_at, deleted_at
+SELECT ` + meetingColumns + `
 FROM meetings
 WHERE deleted_at IS NULL
 ORDER BY uploaded_at DESC;
@@ -78,25 +171,11 @@ ORDER BY uploaded_at DESC;
 
 	var meetings []models.Meeting
 	for rows.Next() {
-		var m models.Meeting
-		err := rows.Scan(
-			&m.ID,
-			&m.Name,
-			&m.Transcript,
-			&m.SummaryText,
-			&m.TasksJSON,
-			&m.TasksStatusNum,
-			&m.ChatHistory,
-			&m.Remark,
-			&m.AudioFilename,
-			&m.UploadedAt,
-			&m.ModifiedAt,
-			&m.DeletedAt,
-		)
+		m, err := scanMeeting(rows)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan meeting row: %w", err)
 		}
-		meetings = append(meetings, m)
+		meetings = append(meetings, *m)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -108,39 +187,46 @@ ORDER BY uploaded_at DESC;
 
 func (r *SQLiteRepository) GetMeetingByID(id int64) (*models.Meeting, error) {
 	query := `
-SELECT id, name, transcript, summary_text, tasks_json, tasks_status_num,
-	   chat_history, remark, audio_filename, uploaded_at, modified_at, deleted_at
+SELECT ` + meetingColumns + `
 FROM meetings
 WHERE id = ? AND deleted_at IS NULL;`
-	row := r.db.QueryRow(query, id)
-	var m models.Meeting
-	err := row.Scan(
-		&m.ID,
-		&m.Name,
-		&m.Transcript,
-		&m.SummaryText,
-		&m.TasksJSON,
-		&m.TasksStatusNum,
-		&m.ChatHistory,
-		&m.Remark,
-		&m.AudioFilename,
-		&m.UploadedAt,
-		&m.ModifiedAt,
-		&m.DeletedAt,
-	)
+	m, err := scanMeeting(r.db.QueryRow(query, id))
 	if err == sql.ErrNoRows {
 		return nil, nil // No meeting found
 	} else if err != nil {
 		return nil, fmt.Errorf("failed to query meeting by ID: %w", err)
 	}
-	return &m, nil
+	return m, nil
+}
+
+// GetMeetingByContentHash finds the earliest meeting whose uploaded content has the given hash.
+func (r *SQLiteRepository) GetMeetingByContentHash(hash string) (*models.Meeting, error) {
+	query := `
+SELECT ` + meetingColumns + `
+FROM meetings
+WHERE content_hash = ? AND deleted_at IS NULL
+ORDER BY id
+LIMIT 1;`
+	m, err := scanMeeting(r.db.QueryRow(query, hash))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to query meeting by content hash: %w", err)
+	}
+	return m, nil
 }
 
+// UpdateMeeting saves a meeting. The summary job state is not written here, only by
+// SetSummaryStatus, so that saving a meeting loaded earlier can't overwrite it.
 func (r *SQLiteRepository) UpdateMeeting(id int64, meeting *models.Meeting) error {
 	query := `
 UPDATE meetings
 SET name = ?, transcript = ?, summary_text = ?, tasks_json = ?, tasks_status_num = ?,
-	chat_history = ?, remark = ?, audio_filename = ?, modified_at = ?
+	chat_history = ?, remark = ?, audio_filename = ?, participants_json = ?, title = ?,
+	description = ?, scheduled_at = ?, content_hash = ?,
+	audio_path = ?, live = ?, redact_allow_json = ?, redact_deny_json = ?,
+	normalization_json = ?, normalized_utterances_json = ?, sections_json = ?, summary_options_json = ?,
+	chapters_json = ?, citations_json = ?, task_items_json = ?, modified_at = ?
 WHERE id = ? AND deleted_at IS NULL;
 `
 	// Ensure the modified_at timestamp is updated
@@ -155,6 +241,22 @@ WHERE id = ? AND deleted_at IS NULL;
 		meeting.ChatHistory,
 		meeting.Remark,
 		meeting.AudioFilename,
+		meeting.ParticipantsJSON,
+		meeting.Title,
+		meeting.Description,
+		meeting.ScheduledAt,
+		meeting.ContentHash,
+		meeting.AudioPath,
+		meeting.Live,
+		meeting.RedactAllowJSON,
+		meeting.RedactDenyJSON,
+		meeting.NormalizationJSON,
+		meeting.NormalizedUtterancesJSON,
+		meeting.SectionsJSON,
+		meeting.SummaryOptionsJSON,
+		meeting.ChaptersJSON,
+		meeting.CitationsJSON,
+		meeting.TaskItemsJSON,
 		meeting.ModifiedAt,
 		id,
 	)
@@ -165,6 +267,323 @@ WHERE id = ? AND deleted_at IS NULL;
 	return nil
 }
 
+// SetTranscript stores a meeting's transcript and content hash
+func (r *SQLiteRepository) SetTranscript(meetingID int64, transcript string, contentHash sql.NullString) error {
+	_, err := r.db.Exec(`UPDATE meetings SET transcript = ?, content_hash = ?, modified_at = ? WHERE id = ? AND deleted_at IS NULL;`,
+		transcript, contentHash, time.Now(), meetingID)
+	if err != nil {
+		return fmt.Errorf("failed to set transcript: %w", err)
+	}
+	return nil
+}
+
+// SetRedactionLists stores a meeting's redaction allow and deny lists
+func (r *SQLiteRepository) SetRedactionLists(meetingID int64, allowJSON, denyJSON sql.NullString) error {
+	_, err := r.db.Exec(`UPDATE meetings SET redact_allow_json = ?, redact_deny_json = ?, modified_at = ? WHERE id = ? AND deleted_at IS NULL;`,
+		allowJSON, denyJSON, time.Now(), meetingID)
+	if err != nil {
+		return fmt.Errorf("failed to set redaction lists: %w", err)
+	}
+	return nil
+}
+
+// SetSummaryOptions stores the options a meeting is summarized with
+func (r *SQLiteRepository) SetSummaryOptions(meetingID int64, optionsJSON sql.NullString) error {
+	_, err := r.db.Exec(`UPDATE meetings SET summary_options_json = ?, modified_at = ? WHERE id = ? AND deleted_at IS NULL;`,
+		optionsJSON, time.Now(), meetingID)
+	if err != nil {
+		return fmt.Errorf("failed to set summary options: %w", err)
+	}
+	return nil
+}
+
+// SetNormalization stores a meeting's normalization settings and normalized utterances
+func (r *SQLiteRepository) SetNormalization(meetingID int64, settingsJSON, normalizedJSON sql.NullString) error {
+	_, err := r.db.Exec(`
+UPDATE meetings
+SET normalization_json = ?, normalized_utterances_json = ?, modified_at = ?
+WHERE id = ? AND deleted_at IS NULL;
+`, settingsJSON, normalizedJSON, time.Now(), meetingID)
+	if err != nil {
+		return fmt.Errorf("failed to set normalization: %w", err)
+	}
+	return nil
+}
+
+// SetSummaryStatus records a state change of the meeting's summary job. Queuing a new
+// job resets its attempts; queuing a retry passes the error of the failed attempt and
+// keeps them. Every start of a run counts as an attempt.
+func (r *SQLiteRepository) SetSummaryStatus(meetingID int64, status string, errMsg string) error {
+	errValue := sql.NullString{String: errMsg, Valid: errMsg != ""}
+	_, err := r.db.Exec(`
+UPDATE meetings
+SET summary_status = ?,
+	summary_error = ?,
+	summary_attempts = CASE WHEN ? = 'queued' AND ? IS NULL THEN 0 WHEN ? = 'running' THEN summary_attempts + 1 ELSE summary_attempts END
+WHERE id = ?;
+`, status, errValue, status, errValue, status, meetingID)
+	if err != nil {
+		return fmt.Errorf("failed to set summary status: %w", err)
+	}
+	return nil
+}
+
+// SaveUtterances replaces the stored utterances of a meeting with the given ones.
+func (r *SQLiteRepository) SaveUtterances(meetingID int64, utterances []models.Utterance) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM utterances WHERE meeting_id = ?;`, meetingID); err != nil {
+		return fmt.Errorf("failed to clear utterances: %w", err)
+	}
+	if err := insertUtterances(tx, meetingID, utterances); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit utterances: %w", err)
+	}
+	return nil
+}
+
+// insertUtterances stores utterances for a meeting that has none, numbering them from 0
+func insertUtterances(tx *sql.Tx, meetingID int64, utterances []models.Utterance) error {
+	if len(utterances) == 0 {
+		return nil
+	}
+	stmt, err := tx.Prepare(`
+INSERT INTO utterances (meeting_id, seq, speaker, start_ms, end_ms, text)
+VALUES (?, ?, ?, ?, ?, ?);
+`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare utterance insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, u := range utterances {
+		if _, err := stmt.Exec(meetingID, i, u.Speaker, u.StartMs, u.EndMs, u.Text); err != nil {
+			return fmt.Errorf("failed to insert utterance %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// AppendUtterances adds utterances after the ones a meeting already has and
+// appends their rendered text to the meeting transcript, atomically. It returns
+// models.ErrMeetingNotLive, storing nothing, if the meeting has been closed.
+func (r *SQLiteRepository) AppendUtterances(meetingID int64, utterances []models.Utterance, transcriptText string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var next int
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(seq) + 1, 0) FROM utterances WHERE meeting_id = ?;`, meetingID).Scan(&next); err != nil {
+		return fmt.Errorf("failed to read last utterance: %w", err)
+	}
+
+	stmt, err := tx.Prepare(`
+INSERT INTO utterances (meeting_id, seq, speaker, start_ms, end_ms, text)
+VALUES (?, ?, ?, ?, ?, ?);
+`)
+	if err != nil {
+		return fmt.Errorf("failed to prepare utterance insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for i, u := range utterances {
+		if _, err := stmt.Exec(meetingID, next+i, u.Speaker, u.StartMs, u.EndMs, u.Text); err != nil {
+			return fmt.Errorf("failed to insert utterance %d: %w", next+i, err)
+		}
+	}
+
+	result, err := tx.Exec(`
+UPDATE meetings
+SET transcript = COALESCE(transcript, '') || ?, modified_at = ?
+WHERE id = ? AND live = 1 AND deleted_at IS NULL;
+`, transcriptText, time.Now(), meetingID)
+	if err != nil {
+		return fmt.Errorf("failed to append transcript: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to check appended transcript: %w", err)
+	} else if n == 0 {
+		return models.ErrMeetingNotLive
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit utterances: %w", err)
+	}
+	return nil
+}
+
+// CloseLiveMeeting ends a live meeting and stores the content hash of its final
+// transcript. The meeting is closed before the transcript is read, so that no
+// utterances can be appended in between. It reports false if the meeting was not live.
+func (r *SQLiteRepository) CloseLiveMeeting(meetingID int64) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`UPDATE meetings SET live = 0, modified_at = ? WHERE id = ? AND live = 1 AND deleted_at IS NULL;`,
+		time.Now(), meetingID)
+	if err != nil {
+		return false, fmt.Errorf("failed to close meeting: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return false, fmt.Errorf("failed to check closed meeting: %w", err)
+	} else if n == 0 {
+		return false, nil
+	}
+
+	var transcript sql.NullString
+	if err := tx.QueryRow(`SELECT transcript FROM meetings WHERE id = ?;`, meetingID).Scan(&transcript); err != nil {
+		return false, fmt.Errorf("failed to read transcript: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE meetings SET content_hash = ? WHERE id = ?;`,
+		models.ContentHash([]byte(transcript.String)), meetingID); err != nil {
+		return false, fmt.Errorf("failed to store content hash: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed to commit closed meeting: %w", err)
+	}
+	return true, nil
+}
+
+// ListUtterances retrieves the utterances of a meeting in transcript order.
+func (r *SQLiteRepository) ListUtterances(meetingID int64) ([]models.Utterance, error) {
+	query := `
+SELECT id, meeting_id, seq, speaker, start_ms, end_ms, text
+FROM utterances
+WHERE meeting_id = ?
+ORDER BY seq;
+`
+	rows, err := r.db.Query(query, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query utterances: %w", err)
+	}
+	defer rows.Close()
+
+	var utterances []models.Utterance
+	for rows.Next() {
+		var u models.Utterance
+		if err := rows.Scan(&u.ID, &u.MeetingID, &u.Seq, &u.Speaker, &u.StartMs, &u.EndMs, &u.Text); err != nil {
+			return nil, fmt.Errorf("failed to scan utterance row: %w", err)
+		}
+		utterances = append(utterances, u)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating utterance rows: %w", err)
+	}
+
+	return utterances, nil
+}
+
+// UpdateTranscript replaces a meeting's transcript and utterances. The previous
+// transcript, utterances and summary are kept as a revision, and the summary and
+// tasks are cleared so they can be regenerated. The content hash follows the new
+// transcript. It returns the revision ID.
+func (r *SQLiteRepository) UpdateTranscript(meetingID int64, transcript string, utterances []models.Utterance) (int64, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+INSERT INTO transcript_revisions (meeting_id, transcript, utterances_json, summary_text, tasks_json, tasks_status_num, created_at)
+SELECT id, transcript,
+       (SELECT json_group_array(json_object(
+               'seq', seq, 'speaker', speaker, 'start_ms', start_ms, 'end_ms', end_ms, 'text', text))
+        FROM (SELECT * FROM utterances WHERE meeting_id = meetings.id ORDER BY seq)),
+       summary_text, tasks_json, tasks_status_num, ?
+FROM meetings
+WHERE id = ? AND deleted_at IS NULL;
+`, time.Now(), meetingID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to save transcript revision: %w", err)
+	}
+	revisionID, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get revision ID: %w", err)
+	}
+
+	if _, err := tx.Exec(`
+UPDATE meetings
+SET transcript = ?, content_hash = ?, summary_text = NULL, tasks_json = NULL, tasks_status_num = 0,
+	sections_json = NULL, chat_history = NULL, normalized_utterances_json = NULL, summary_version = NULL,
+	chapters_json = NULL, citations_json = NULL, task_items_json = NULL, modified_at = ?
+WHERE id = ? AND deleted_at IS NULL;
+`, transcript, models.ContentHash([]byte(transcript)), time.Now(), meetingID); err != nil {
+		return 0, fmt.Errorf("failed to update transcript: %w", err)
+	}
+
+	if _, err := tx.Exec(`DELETE FROM utterances WHERE meeting_id = ?;`, meetingID); err != nil {
+		return 0, fmt.Errorf("failed to clear utterances: %w", err)
+	}
+	for i, u := range utterances {
+		if _, err := tx.Exec(`
+INSERT INTO utterances (meeting_id, seq, speaker, start_ms, end_ms, text)
+VALUES (?, ?, ?, ?, ?, ?);
+`, meetingID, i, u.Speaker, u.StartMs, u.EndMs, u.Text); err != nil {
+			return 0, fmt.Errorf("failed to insert utterance %d: %w", i, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transcript update: %w", err)
+	}
+	return revisionID, nil
+}
+
+// ListTranscriptRevisions retrieves a meeting's transcript revisions, newest first.
+func (r *SQLiteRepository) ListTranscriptRevisions(meetingID int64) ([]models.TranscriptRevision, error) {
+	query := `
+SELECT id, meeting_id, transcript, utterances_json, summary_text, tasks_json, tasks_status_num, created_at
+FROM transcript_revisions
+WHERE meeting_id = ?
+ORDER BY id DESC;
+`
+	rows, err := r.db.Query(query, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query transcript revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []models.TranscriptRevision
+	for rows.Next() {
+		var rev models.TranscriptRevision
+		err := rows.Scan(
+			&rev.ID,
+			&rev.MeetingID,
+			&rev.Transcript,
+			&rev.UtterancesJSON,
+			&rev.SummaryText,
+			&rev.TasksJSON,
+			&rev.TasksStatusNum,
+			&rev.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan transcript revision row: %w", err)
+		}
+		revisions = append(revisions, rev)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating transcript revision rows: %w", err)
+	}
+
+	return revisions, nil
+}
+
 // InitSchema creates the necessary tables if they don't exist.
 func InitSchema(db *sql.DB) error {
 	schema := `
@@ -184,11 +603,263 @@ CREATE TABLE IF NOT EXISTS meetings (
 );
 
 CREATE INDEX IF NOT EXISTS idx_meetings_name ON meetings (name);
+
+CREATE TABLE IF NOT EXISTS utterances (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    meeting_id INTEGER NOT NULL REFERENCES meetings (id),
+    seq INTEGER NOT NULL,
+    speaker TEXT NOT NULL,
+    start_ms INTEGER NOT NULL,
+    end_ms INTEGER NOT NULL,
+    text TEXT NOT NULL,
+    UNIQUE (meeting_id, seq)
+);
+
+CREATE TABLE IF NOT EXISTS transcript_revisions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    meeting_id INTEGER NOT NULL REFERENCES meetings (id),
+    transcript TEXT,
+    utterances_json TEXT,
+    summary_text TEXT,
+    tasks_json TEXT,
+    tasks_status_num INTEGER DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_transcript_revisions_meeting ON transcript_revisions (meeting_id);
+
+CREATE TABLE IF NOT EXISTS participants (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    display_name TEXT NOT NULL UNIQUE COLLATE NOCASE,
+    email TEXT,
+    aliases_json TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    modified_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS meeting_speakers (
+    meeting_id INTEGER NOT NULL REFERENCES meetings (id),
+    speaker TEXT NOT NULL,
+    participant_id INTEGER REFERENCES participants (id),
+    manual INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (meeting_id, speaker)
+);
+
+CREATE INDEX IF NOT EXISTS idx_meeting_speakers_participant ON meeting_speakers (participant_id);
+
+CREATE TABLE IF NOT EXISTS jobs (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    meeting_id INTEGER NOT NULL REFERENCES meetings (id),
+    status TEXT NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    max_attempts INTEGER NOT NULL,
+    last_error TEXT,
+    run_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_status_run_at ON jobs (status, run_at);
+
+CREATE TABLE IF NOT EXISTS summary_versions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    meeting_id INTEGER NOT NULL REFERENCES meetings (id),
+    version INTEGER NOT NULL,
+    summary_text TEXT NOT NULL,
+    tasks_json TEXT,
+    sections_json TEXT,
+    model TEXT,
+    template TEXT,
+    instructions TEXT,
+    prompt_tokens INTEGER NOT NULL DEFAULT 0,
+    completion_tokens INTEGER NOT NULL DEFAULT 0,
+    total_tokens INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (meeting_id, version)
+);
 `
 	_, err := db.Exec(schema)
 	if err != nil {
 		return fmt.Errorf("failed to initialize database schema: %w", err)
 	}
+	if err := addMissingColumns(db, "meetings", meetingMigrations); err != nil {
+		return fmt.Errorf("failed to migrate meetings table: %w", err)
+	}
+	if err := addMissingColumns(db, "summary_versions", summaryVersionMigrations); err != nil {
+		return fmt.Errorf("failed to migrate summary versions table: %w", err)
+	}
+	if _, err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_meetings_content_hash ON meetings (content_hash);`); err != nil {
+		return fmt.Errorf("failed to create content hash index: %w", err)
+	}
+	if err := createQueuedJobIndex(db); err != nil {
+		return err
+	}
+	if err := backfillContentHashes(db); err != nil {
+		return err
+	}
+	if err := backfillSummaryStatus(db); err != nil {
+		return err
+	}
+	if err := backfillSummaryVersions(db); err != nil {
+		return err
+	}
 	fmt.Println("Database schema initialized successfully.")
 	return nil
 }
+
+// columnMigration is a column added to a table after its initial CREATE TABLE
+type columnMigration struct {
+	name       string
+	definition string
+}
+
+// meetingMigrations lists the meetings columns added after the initial schema.
+// They are applied to new and existing databases alike.
+var meetingMigrations = []columnMigration{
+	{"participants_json", "TEXT"},
+	{"title", "TEXT"},
+	{"description", "TEXT"},
+	{"scheduled_at", "TIMESTAMP NULL"},
+	{"content_hash", "TEXT"},
+	{"audio_path", "TEXT"},
+	{"live", "INTEGER NOT NULL DEFAULT 0"},
+	{"redact_allow_json", "TEXT"},
+	{"redact_deny_json", "TEXT"},
+	{"normalization_json", "TEXT"},
+	{"normalized_utterances_json", "TEXT"},
+	{"sections_json", "TEXT"},
+	{"summary_status", "TEXT"},
+	{"summary_error", "TEXT"},
+	{"summary_attempts", "INTEGER NOT NULL DEFAULT 0"},
+	{"summary_options_json", "TEXT"},
+	{"summary_version", "INTEGER"},
+	{"chapters_json", "TEXT"},
+	{"citations_json", "TEXT"},
+	{"task_items_json", "TEXT"},
+}
+
+// summaryVersionMigrations lists the summary_versions columns added after the initial schema
+var summaryVersionMigrations = []columnMigration{
+	{"citations_json", "TEXT"},
+	{"task_items_json", "TEXT"},
+	{"chapters_json", "TEXT"},
+}
+
+// addMissingColumns adds each migration column that the table does not have yet.
+func addMissingColumns(db *sql.DB, table string, migrations []columnMigration) error {
+	rows, err := db.Query(`PRAGMA table_info(` + table + `);`)
+	if err != nil {
+		return fmt.Errorf("failed to read table info: %w", err)
+	}
+	existing := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid        int
+			name       string
+			colType    string
+			notNull    int
+			defaultVal sql.NullString
+			pk         int
+		)
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultVal, &pk); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan table info: %w", err)
+		}
+		existing[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating table info: %w", err)
+	}
+
+	for _, m := range migrations {
+		if existing[m.name] {
+			continue
+		}
+		if _, err := db.Exec(`ALTER TABLE ` + table + ` ADD COLUMN ` + m.name + ` ` + m.definition + `;`); err != nil {
+			return fmt.Errorf("failed to add column %s: %w", m.name, err)
+		}
+	}
+	return nil
+}
+
+// createQueuedJobIndex allows only one queued job of a kind per meeting. Duplicates
+// queued before the index existed are dropped first, keeping the oldest.
+func createQueuedJobIndex(db *sql.DB) error {
+	if _, err := db.Exec(`
+DELETE FROM jobs
+WHERE status = 'queued'
+  AND id NOT IN (SELECT MIN(id) FROM jobs WHERE status = 'queued' GROUP BY kind, meeting_id);
+`); err != nil {
+		return fmt.Errorf("failed to remove duplicate queued jobs: %w", err)
+	}
+	if _, err := db.Exec(`CREATE UNIQUE INDEX IF NOT EXISTS idx_jobs_queued ON jobs (kind, meeting_id) WHERE status = 'queued';`); err != nil {
+		return fmt.Errorf("failed to create queued job index: %w", err)
+	}
+	return nil
+}
+
+// backfillContentHashes hashes the transcripts of meetings created before content hashes were stored.
+func backfillContentHashes(db *sql.DB) error {
+	rows, err := db.Query(`SELECT id, transcript FROM meetings WHERE content_hash IS NULL AND transcript IS NOT NULL;`)
+	if err != nil {
+		return fmt.Errorf("failed to query meetings without content hash: %w", err)
+	}
+	hashes := make(map[int64]string)
+	for rows.Next() {
+		var id int64
+		var transcript string
+		if err := rows.Scan(&id, &transcript); err != nil {
+			rows.Close()
+			return fmt.Errorf("failed to scan meeting transcript: %w", err)
+		}
+		hashes[id] = models.ContentHash([]byte(transcript))
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating meeting transcripts: %w", err)
+	}
+
+	for id, hash := range hashes {
+		if _, err := db.Exec(`UPDATE meetings SET content_hash = ? WHERE id = ?;`, hash, id); err != nil {
+			return fmt.Errorf("failed to backfill content hash for meeting %d: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// backfillSummaryStatus sets the summary state of meetings created before it was tracked.
+// Meetings without a summary had their generation lost, so they are marked as failed.
+func backfillSummaryStatus(db *sql.DB) error {
+	_, err := db.Exec(`
+UPDATE meetings
+SET summary_status = CASE WHEN COALESCE(summary_text, '') != '' THEN 'succeeded' ELSE 'failed' END,
+	summary_error = CASE WHEN COALESCE(summary_text, '') != '' THEN NULL ELSE 'summary was not generated' END
+WHERE summary_status IS NULL AND live = 0;
+`)
+	if err != nil {
+		return fmt.Errorf("failed to backfill summary status: %w", err)
+	}
+	return nil
+}
+
+// backfillSummaryVersions keeps the summaries of meetings summarized before versions
+// were stored as their first version. Their model and token usage are unknown.
+func backfillSummaryVersions(db *sql.DB) error {
+	_, err := db.Exec(`
+INSERT INTO summary_versions (meeting_id, version, summary_text, tasks_json, sections_json, created_at)
+SELECT id, 1, summary_text, tasks_json, sections_json, modified_at
+FROM meetings
+WHERE COALESCE(summary_text, '') != '' AND summary_version IS NULL
+	AND NOT EXISTS (SELECT 1 FROM summary_versions WHERE summary_versions.meeting_id = meetings.id);
+
+UPDATE meetings SET summary_version = 1
+WHERE COALESCE(summary_text, '') != '' AND summary_version IS NULL
+	AND EXISTS (SELECT 1 FROM summary_versions WHERE summary_versions.meeting_id = meetings.id AND version = 1);
+`)
+	if err != nil {
+		return fmt.Errorf("failed to backfill summary versions: %w", err)
+	}
+	return nil
+}
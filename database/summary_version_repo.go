@@ -0,0 +1,137 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"meetingagent/models"
+)
+
+const summaryVersionColumns = `id, meeting_id, version, summary_text, tasks_json, sections_json, citations_json, task_items_json, chapters_json,
+	   model, template, instructions, prompt_tokens, completion_tokens, total_tokens, created_at`
+
+func scanSummaryVersion(row rowScanner) (*models.SummaryVersion, error) {
+	var v models.SummaryVersion
+	err := row.Scan(&v.ID, &v.MeetingID, &v.Version, &v.SummaryText, &v.TasksJSON, &v.SectionsJSON, &v.CitationsJSON, &v.TaskItemsJSON, &v.ChaptersJSON,
+		&v.Model, &v.Template, &v.Instructions,
+		&v.Usage.PromptTokens, &v.Usage.CompletionTokens, &v.Usage.TotalTokens, &v.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// updateSummaryColumns writes the summary columns of a meeting. Only these are written,
+// so that changes made while the summary was generated, such as a transcript edit, are kept.
+func updateSummaryColumns(tx *sql.Tx, meeting *models.Meeting) error {
+	meeting.ModifiedAt = time.Now()
+	_, err := tx.Exec(`
+UPDATE meetings
+SET summary_text = ?, tasks_json = ?, tasks_status_num = ?, sections_json = ?,
+	chapters_json = ?, citations_json = ?, task_items_json = ?, chat_history = ?, modified_at = ?
+WHERE id = ? AND deleted_at IS NULL;
+`, meeting.SummaryText, meeting.TasksJSON, meeting.TasksStatusNum, meeting.SectionsJSON,
+		meeting.ChaptersJSON, meeting.CitationsJSON, meeting.TaskItemsJSON, meeting.ChatHistory, meeting.ModifiedAt,
+		meeting.ID)
+	if err != nil {
+		return fmt.Errorf("failed to save summary: %w", err)
+	}
+	return nil
+}
+
+// SaveSummary stores a generated summary of a meeting together with its next summary
+// version, which becomes the current one, and returns the version number
+func (r *SQLiteRepository) SaveSummary(meeting *models.Meeting, version *models.SummaryVersion) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateSummaryColumns(tx, meeting); err != nil {
+		return 0, err
+	}
+	if err := tx.QueryRow(`SELECT COALESCE(MAX(version), 0) + 1 FROM summary_versions WHERE meeting_id = ?;`,
+		version.MeetingID).Scan(&version.Version); err != nil {
+		return 0, fmt.Errorf("failed to number summary version: %w", err)
+	}
+	version.CreatedAt = time.Now()
+	result, err := tx.Exec(`
+INSERT INTO summary_versions (meeting_id, version, summary_text, tasks_json, sections_json, citations_json, task_items_json,
+	   chapters_json, model, template, instructions, prompt_tokens, completion_tokens, total_tokens, created_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+`, version.MeetingID, version.Version, version.SummaryText, version.TasksJSON, version.SectionsJSON, version.CitationsJSON,
+		version.TaskItemsJSON, version.ChaptersJSON, version.Model, version.Template, version.Instructions,
+		version.Usage.PromptTokens, version.Usage.CompletionTokens, version.Usage.TotalTokens, version.CreatedAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert summary version: %w", err)
+	}
+	if version.ID, err = result.LastInsertId(); err != nil {
+		return 0, fmt.Errorf("failed to get last insert ID: %w", err)
+	}
+
+	if _, err := tx.Exec(`UPDATE meetings SET summary_version = ? WHERE id = ?;`, version.Version, version.MeetingID); err != nil {
+		return 0, fmt.Errorf("failed to set current summary version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return version.Version, nil
+}
+
+// PinSummary stores a meeting's summary as taken from one of its summary versions and
+// makes that version the current one
+func (r *SQLiteRepository) PinSummary(meeting *models.Meeting, version int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := updateSummaryColumns(tx, meeting); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE meetings SET summary_version = ? WHERE id = ?;`, version, meeting.ID); err != nil {
+		return fmt.Errorf("failed to set current summary version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ListSummaryVersions retrieves the summary versions of a meeting, newest first
+func (r *SQLiteRepository) ListSummaryVersions(meetingID int64) ([]models.SummaryVersion, error) {
+	rows, err := r.db.Query(`SELECT `+summaryVersionColumns+` FROM summary_versions WHERE meeting_id = ? ORDER BY version DESC;`, meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query summary versions: %w", err)
+	}
+	defer rows.Close()
+
+	var versions []models.SummaryVersion
+	for rows.Next() {
+		v, err := scanSummaryVersion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan summary version row: %w", err)
+		}
+		versions = append(versions, *v)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating summary version rows: %w", err)
+	}
+	return versions, nil
+}
+
+// GetSummaryVersion retrieves a summary version by its number, returning nil if it doesn't exist
+func (r *SQLiteRepository) GetSummaryVersion(meetingID int64, version int) (*models.SummaryVersion, error) {
+	row := r.db.QueryRow(`SELECT `+summaryVersionColumns+` FROM summary_versions WHERE meeting_id = ? AND version = ?;`, meetingID, version)
+	v, err := scanSummaryVersion(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get summary version: %w", err)
+	}
+	return v, nil
+}
@@ -9,6 +9,7 @@ require (
 	github.com/cloudwego/eino-ext/components/model/ark v0.1.6
 	github.com/cloudwego/hertz v0.7.3
 	github.com/hertz-contrib/sse v0.0.1
+	github.com/hertz-contrib/websocket v0.1.0
 	github.com/mark3labs/mcp-go v0.22.0
 	github.com/mattn/go-sqlite3 v1.14.28
 	gopkg.in/yaml.v3 v3.0.1
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"meetingagent/models"
+	"meetingagent/services"
+	"meetingagent/transcript"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// CreateLiveMeeting handles starting a meeting that receives its transcript while it is in progress
+func CreateLiveMeeting(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	var req models.CreateLiveMeetingRequest
+	if body := c.Request.Body(); len(strings.TrimSpace(string(body))) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+
+	currentTime := time.Now()
+	metadata := meetingMetadata{
+		Title:        strings.TrimSpace(req.Title),
+		Description:  strings.TrimSpace(req.Description),
+		Remark:       strings.TrimSpace(req.Remark),
+		Participants: req.Participants,
+		ScheduledAt:  currentTime,
+	}
+	if req.ScheduledAt != "" {
+		scheduledAt, err := parseScheduledAt(req.ScheduledAt)
+		if err != nil {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+			return
+		}
+		metadata.ScheduledAt = scheduledAt
+	}
+
+	name := "live_" + currentTime.Format("20060102150405")
+	if metadata.Title != "" {
+		name = metadata.Title
+	}
+	meeting := &models.Meeting{
+		Name:          name,
+		AudioFilename: name,
+		Transcript:    sql.NullString{String: "", Valid: true},
+		Live:          true,
+		UploadedAt:    currentTime,
+		ModifiedAt:    currentTime,
+	}
+	if err := metadata.apply(meeting); err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
+
+	newID, err := services.CreateMeetingWithUniqueName(meetingRepo, meeting, nil)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to create meeting record: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusCreated, models.PostMeetingResponse{ID: newID})
+}
+
+// AppendUtterances handles adding a batch of utterances to a live meeting.
+// The body may be in any transcript format accepted by POST /meeting.
+func AppendUtterances(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+	if _, ok := getLiveMeeting(c, meetingID); !ok {
+		return
+	}
+
+	doc, err := transcript.Parse(string(c.GetHeader("X-File-Name")), string(c.ContentType()), c.Request.Body())
+	if err != nil {
+		writeParseError(c, err)
+		return
+	}
+	if len(doc.Utterances) == 0 {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "Body contains no utterances in a recognised transcript format"})
+		return
+	}
+
+	if err := meetingRepo.AppendUtterances(meetingID, doc.Utterances, transcript.Render(doc.Utterances)); errors.Is(err, models.ErrMeetingNotLive) {
+		c.JSON(consts.StatusConflict, utils.H{"error": "Meeting is not live"})
+		return
+	} else if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to append utterances: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, models.AppendUtterancesResponse{Appended: len(doc.Utterances)})
+}
+
+// CloseLiveMeeting handles ending a live meeting and starts generating its final summary
+func CloseLiveMeeting(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+	if _, ok := getLiveMeeting(c, meetingID); !ok {
+		return
+	}
+
+	// Closed in one step so that utterances appended meanwhile are not lost
+	closed, err := meetingRepo.CloseLiveMeeting(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to close meeting: " + err.Error()})
+		return
+	}
+	if !closed {
+		c.JSON(consts.StatusConflict, utils.H{"error": "Meeting is not live"})
+		return
+	}
+
+	// Generate the final summary asynchronously
+	if err := summarizeInBackground(meetingID); err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to queue summary: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusAccepted, models.PostMeetingResponse{ID: meetingID})
+}
+
+// getLiveMeeting loads a meeting that must still be live, writing an error response otherwise
+func getLiveMeeting(c *app.RequestContext, meetingID int64) (*models.Meeting, bool) {
+	meeting, err := meetingRepo.GetMeetingByID(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve meeting: " + err.Error()})
+		return nil, false
+	}
+	if meeting == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "Meeting not found"})
+		return nil, false
+	}
+	if !meeting.Live {
+		c.JSON(consts.StatusConflict, utils.H{"error": "Meeting is not live"})
+		return nil, false
+	}
+	return meeting, true
+}
@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"meetingagent/config"
+	"meetingagent/models"
+	"meetingagent/services"
+	"meetingagent/transcript"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+	"github.com/hertz-contrib/websocket"
+)
+
+var liveUpgrader = websocket.HertzUpgrader{}
+
+// liveSession is the state of one WebSocket connection streaming into a live meeting
+type liveSession struct {
+	meetingID int64
+	conn      *websocket.Conn
+	writeMu   sync.Mutex // The connection supports one concurrent writer
+	closed    bool       // Set under writeMu once the reader is done; nothing is written after
+
+	mu          sync.Mutex
+	dirty       bool // Final segments arrived since the last rolling summary
+	summarizing bool
+}
+
+// send writes a message unless the session is closed, e.g. for a rolling summary that
+// finishes during teardown
+func (s *liveSession) send(msg models.LiveServerMessage) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if s.closed {
+		return
+	}
+	if err := s.conn.WriteJSON(msg); err != nil {
+		log.Printf("Error writing to live socket of meeting %d: %v", s.meetingID, err)
+	}
+}
+
+// close stops all further writes, first sending a close message with reason if it is set
+func (s *liveSession) close(reason string) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	if reason != "" {
+		s.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, reason))
+	}
+}
+
+// LiveMeetingSocket handles a WebSocket connection pushing transcript segments into a live meeting.
+// Every segment is acknowledged, final segments are stored as utterances, and a rolling summary
+// is sent back at the configured interval.
+func LiveMeetingSocket(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+	if _, ok := getLiveMeeting(c, meetingID); !ok {
+		return
+	}
+
+	err := liveUpgrader.Upgrade(c, func(conn *websocket.Conn) {
+		session := &liveSession{meetingID: meetingID, conn: conn}
+		defer session.close("")
+
+		done := make(chan struct{})
+		defer close(done)
+		if interval := config.AppConfig.Live.SummaryIntervalSeconds; interval > 0 {
+			go session.summaryLoop(ctx, time.Duration(interval)*time.Second, done)
+		}
+
+		for {
+			var segment models.LiveSegment
+			if err := conn.ReadJSON(&segment); err != nil {
+				if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+					log.Printf("Live socket of meeting %d closed: %v", meetingID, err)
+				}
+				return
+			}
+			if !session.handleSegment(segment) {
+				// The meeting was closed meanwhile
+				session.close("meeting is not live")
+				return
+			}
+		}
+	})
+	if err != nil {
+		log.Printf("Failed to upgrade live socket for meeting %d: %v", meetingID, err)
+	}
+}
+
+// handleSegment validates a segment, stores it if final and acknowledges it.
+// It reports false if the meeting is no longer live, after sending an error.
+func (s *liveSession) handleSegment(segment models.LiveSegment) bool {
+	reply := models.LiveServerMessage{Type: "ack", SegmentID: segment.SegmentID, Final: segment.Final}
+
+	segment.Text = strings.TrimSpace(segment.Text)
+	switch {
+	case segment.Text == "":
+		reply = models.LiveServerMessage{Type: "error", SegmentID: segment.SegmentID, Error: "text is empty"}
+	case segment.StartMs < 0 || segment.EndMs < segment.StartMs:
+		reply = models.LiveServerMessage{Type: "error", SegmentID: segment.SegmentID, Error: "invalid start_ms/end_ms"}
+	case segment.Final:
+		utterances := []models.Utterance{{
+			Speaker: strings.TrimSpace(segment.Speaker),
+			StartMs: segment.StartMs,
+			EndMs:   segment.EndMs,
+			Text:    segment.Text,
+		}}
+		err := meetingRepo.AppendUtterances(s.meetingID, utterances, transcript.Render(utterances))
+		if errors.Is(err, models.ErrMeetingNotLive) {
+			s.send(models.LiveServerMessage{Type: "error", SegmentID: segment.SegmentID, Error: "Meeting is not live"})
+			return false
+		}
+		if err != nil {
+			reply = models.LiveServerMessage{Type: "error", SegmentID: segment.SegmentID, Error: "Failed to store segment: " + err.Error()}
+			break
+		}
+		s.mu.Lock()
+		s.dirty = true
+		s.mu.Unlock()
+	}
+
+	s.send(reply)
+	return true
+}
+
+// summaryLoop periodically summarizes the meeting so far while new final segments keep arriving
+func (s *liveSession) summaryLoop(ctx context.Context, interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if !s.dirty || s.summarizing {
+				s.mu.Unlock()
+				continue
+			}
+			s.dirty = false
+			s.summarizing = true
+			s.mu.Unlock()
+
+			go func() {
+				defer func() {
+					s.mu.Lock()
+					s.summarizing = false
+					s.mu.Unlock()
+				}()
+				s.sendRollingSummary(ctx)
+			}()
+		}
+	}
+}
+
+// sendRollingSummary summarizes the meeting so far and sends the summary, unless the
+// connection closed while it was generated
+func (s *liveSession) sendRollingSummary(ctx context.Context) {
+	meeting, err := meetingRepo.GetMeetingByID(s.meetingID)
+	if err != nil || meeting == nil {
+		log.Printf("Error loading meeting %d for rolling summary: %v", s.meetingID, err)
+		return
+	}
+
+	utterances, err := meetingRepo.ListUtterances(s.meetingID)
+	if err != nil {
+		log.Printf("Error loading utterances of meeting %d for rolling summary: %v", s.meetingID, err)
+		return
+	}
+
+	summary, _, err := services.GetMeetingSummary(ctx, meeting, utterances)
+	if err != nil {
+		log.Printf("Error generating rolling summary for meeting %d: %v", s.meetingID, err)
+		return
+	}
+
+	s.send(models.LiveServerMessage{Type: "summary", Summary: summary})
+}
@@ -4,17 +4,20 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"os"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/cloudwego/eino/schema"
 
 	"meetingagent/models"
+	"meetingagent/redact"
 	"meetingagent/services"
+	"meetingagent/transcript"
 
 	"github.com/cloudwego/hertz/pkg/app"
 	"github.com/cloudwego/hertz/pkg/common/utils"
@@ -44,9 +47,8 @@ func GetMeetingTasks(ctx context.Context, c *app.RequestContext) {
 	}
 
 	// Get meeting from repository
-	meeting, err := meetingRepo.GetMeetingByID(meetingID)
-	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve meeting: " + err.Error()})
+	meeting, ok := getMeeting(c, meetingID)
+	if !ok {
 		return
 	}
 
@@ -54,6 +56,7 @@ func GetMeetingTasks(ctx context.Context, c *app.RequestContext) {
 		c.JSON(consts.StatusOK, utils.H{
 			"tasks":            []string{},
 			"tasks_status_num": 0,
+			"items":            []models.TaskItem{},
 		})
 		return
 	}
@@ -65,10 +68,29 @@ func GetMeetingTasks(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	c.JSON(consts.StatusOK, utils.H{
+	response := utils.H{
 		"tasks":            tasks,
 		"tasks_status_num": meeting.TasksStatusNum,
-	})
+		"items":            taskItems(meeting, tasks),
+	}
+	// The transcript ranges each task is based on, if the summary cited any
+	if citations := meeting.Citations(); citations != nil && len(citations.Tasks) == len(tasks) {
+		response["citations"] = citations.Tasks
+	}
+	c.JSON(consts.StatusOK, response)
+}
+
+// taskItems returns the structured tasks of a meeting's summary. Summaries without
+// them, e.g. when the details could not be extracted, get items holding just the task.
+func taskItems(meeting *models.Meeting, tasks []string) []models.TaskItem {
+	if items := meeting.TaskItems(); items != nil && len(items) == len(tasks) {
+		return items
+	}
+	items := make([]models.TaskItem, len(tasks))
+	for i, task := range tasks {
+		items[i] = models.TaskItem{Title: task}
+	}
+	return items
 }
 
 // --- Placeholder for Repository Dependency ---
@@ -80,22 +102,64 @@ func SetMeetingRepository(repo models.MeetingRepository) {
 	meetingRepo = repo
 }
 
-// CreateMeeting handles the creation of a new meeting from raw JSON content
+var jobQueue *services.JobQueue
+
+// SetJobQueue sets the queue that runs transcription and summary jobs
+func SetJobQueue(queue *services.JobQueue) {
+	jobQueue = queue
+}
+
+// CreateMeeting handles the creation of a new meeting from a raw transcript body or a multipart file upload
 func CreateMeeting(ctx context.Context, c *app.RequestContext) {
 	if meetingRepo == nil {
 		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
 		return
 	}
 
-	// Get file content
-	body, err := c.Body()
+	upload, err := readMeetingUpload(c)
 	if err != nil {
-		c.JSON(consts.StatusBadRequest, utils.H{"error": "Failed to read request body"})
+		c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
 		return
 	}
 
-	// Get the original filename from header
-	fileName := string(c.GetHeader("X-File-Name"))
+	// Audio is kept on disk and transcribed in the background; text is parsed right away
+	isAudio := isAudioUpload(upload)
+	doc := &transcript.Document{}
+	if isAudio {
+		if services.DefaultTranscriber == nil {
+			c.JSON(consts.StatusNotImplemented, utils.H{"error": "Audio transcription is not configured"})
+			return
+		}
+	} else {
+		// Recognise structured transcript formats; unknown content is kept as opaque text
+		doc, err = transcript.Parse(upload.FileName, upload.ContentType, upload.Body)
+		if err != nil {
+			writeParseError(c, err)
+			return
+		}
+	}
+
+	// Detect re-uploads of the same content before doing any work
+	contentHash := models.ContentHash(upload.Body)
+	existing, err := meetingRepo.GetMeetingByContentHash(contentHash)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to check for duplicate meeting: " + err.Error()})
+		return
+	}
+	if existing != nil {
+		if upload.OnDuplicate == duplicateReject {
+			c.JSON(consts.StatusConflict, utils.H{
+				"error":         fmt.Sprintf("Transcript was already uploaded as meeting %d (%s)", existing.ID, existing.Name),
+				"existing_id":   existing.ID,
+				"existing_name": existing.Name,
+			})
+			return
+		}
+		c.JSON(consts.StatusOK, models.PostMeetingResponse{ID: existing.ID, Duplicate: true})
+		return
+	}
+
+	fileName := upload.FileName
 	if fileName == "" {
 		fileName = "meeting_" + time.Now().Format("20060102150405")
 	}
@@ -104,73 +168,149 @@ func CreateMeeting(ctx context.Context, c *app.RequestContext) {
 	meeting := &models.Meeting{
 		Name:          fileName,
 		AudioFilename: fileName,
-		Transcript:    sql.NullString{String: string(body), Valid: true},
+		Transcript:    sql.NullString{String: doc.Text, Valid: !isAudio},
+		ContentHash:   sql.NullString{String: contentHash, Valid: true},
 		UploadedAt:    currentTime,
 		ModifiedAt:    currentTime,
 	}
+	if err := upload.apply(meeting); err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": err.Error()})
+		return
+	}
 
-	// Try to create with original name
-	newID, err := meetingRepo.CreateMeeting(meeting)
-	if err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed") {
-		// On name conflict, generate new name with timestamp and try again
-		meeting.Name = "meeting_" + time.Now().Format("20060102150405")
-		meeting.AudioFilename = meeting.Name
-		newID, err = meetingRepo.CreateMeeting(meeting)
+	if isAudio {
+		audioPath, err := saveAudio(upload)
 		if err != nil {
-			c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to create meeting record: " + err.Error()})
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to store audio: " + err.Error()})
 			return
 		}
-	} else if err != nil {
+		meeting.AudioPath = sql.NullString{String: audioPath, Valid: true}
+	}
+
+	meeting.SummaryStatus = sql.NullString{String: models.SummaryQueued, Valid: true}
+	newID, err := services.CreateMeetingWithUniqueName(meetingRepo, meeting, doc.Utterances)
+	if err != nil {
+		if meeting.AudioPath.Valid {
+			os.Remove(meeting.AudioPath.String)
+		}
 		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to create meeting record: " + err.Error()})
 		return
 	}
 
-	// Generate summary asynchronously
-	go func(meetingID int64, transcript string) {
-		sr, err := services.GetMeetingSummary(ctx, transcript)
-		if err != nil {
-			fmt.Printf("Error generating summary for meeting %d: %v\n", meetingID, err)
-			return
+	// Transcribe audio if needed, then generate the summary in the background
+	kind := models.JobSummarize
+	if isAudio {
+		kind = models.JobTranscribe
+	}
+	if err := jobQueue.Enqueue(kind, newID); err != nil {
+		// The meeting is kept, so that a retry finds it, but not left waiting on a job that doesn't exist
+		if statusErr := meetingRepo.SetSummaryStatus(newID, models.SummaryFailed, "failed to queue summary: "+err.Error()); statusErr != nil {
+			log.Printf("Error marking summary of meeting %d failed: %v", newID, statusErr)
 		}
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to queue summary: " + err.Error()})
+		return
+	}
 
-		jsonByte, marshalErr := json.Marshal(sr)
-		if marshalErr != nil {
-			fmt.Printf("Error marshalling summary response for meeting %d: %v\n", meetingID, marshalErr)
-			return
-		}
-		// Parse the summary response
-		var summaryResp models.SummaryResponse
-		if err := json.Unmarshal(jsonByte, &summaryResp); err != nil {
-			fmt.Printf("Error unmarshalling summary response for meeting %d: %v\n", meetingID, err)
-			return
-		}
+	response := models.PostMeetingResponse{
+		ID: newID,
+	}
+	c.JSON(consts.StatusCreated, response)
+}
 
-		// Store summary text and tasks separately
-		meeting.SummaryText = sql.NullString{String: summaryResp.Summary, Valid: true}
+// summarizeInBackground queues a job that regenerates the meeting's summary
+func summarizeInBackground(meetingID int64) error {
+	return jobQueue.Enqueue(models.JobSummarize, meetingID)
+}
 
-		// Convert tasks array to JSON string
-		tasksJSON, err := json.Marshal(summaryResp.Tasks)
-		if err != nil {
-			fmt.Printf("Error marshalling tasks for meeting %d: %v\n", meetingID, err)
-			return
-		}
-		meeting.TasksJSON = sql.NullString{String: string(tasksJSON), Valid: true}
+// writeParseError reports a transcript that failed validation as a 400 with the individual issues
+func writeParseError(c *app.RequestContext, err error) {
+	var parseErr *transcript.ParseError
+	if errors.As(err, &parseErr) {
+		c.JSON(consts.StatusBadRequest, utils.H{
+			"error":  parseErr.Error(),
+			"format": parseErr.Format,
+			"issues": parseErr.Issues,
+		})
+		return
+	}
+	c.JSON(consts.StatusBadRequest, utils.H{"error": "Failed to parse transcript: " + err.Error()})
+}
 
-		// Initialize tasks_status_num as 0
-		meeting.TasksStatusNum = 0
+// queryMeetingID reads the meeting_id query parameter, writing a 400 response if it is missing or invalid
+func queryMeetingID(c *app.RequestContext) (int64, bool) {
+	return queryID(c, "meeting_id")
+}
 
-		meeting.ChatHistory = sql.NullString{String: string(jsonByte), Valid: true}
-		meeting.ModifiedAt = time.Now()
-		if updateErr := meetingRepo.UpdateMeeting(meetingID, meeting); updateErr != nil {
-			fmt.Printf("Error updating meeting %d with summary: %v\n", meetingID, updateErr)
+// queryID reads an ID query parameter, writing a 400 response if it is missing or invalid
+func queryID(c *app.RequestContext, name string) (int64, bool) {
+	idStr := c.Query(name)
+	if idStr == "" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": name + " is required"})
+		return 0, false
+	}
+
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "Invalid " + name + " format"})
+		return 0, false
+	}
+	return id, true
+}
+
+// getMeeting loads a meeting, writing an error response if it doesn't exist
+func getMeeting(c *app.RequestContext, meetingID int64) (*models.Meeting, bool) {
+	meeting, err := meetingRepo.GetMeetingByID(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve meeting: " + err.Error()})
+		return nil, false
+	}
+	if meeting == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "Meeting not found"})
+		return nil, false
+	}
+	return meeting, true
+}
+
+// GetMeetingUtterances handles retrieving the parsed utterances of a meeting
+func GetMeetingUtterances(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+
+	meeting, err := meetingRepo.GetMeetingByID(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve meeting: " + err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "Meeting not found"})
+		return
+	}
+
+	// With normalized=true the normalized utterances given to the summary model are
+	// returned, falling back to the originals when the meeting has none
+	var utterances []models.Utterance
+	if c.Query("normalized") == "true" {
+		utterances = meeting.NormalizedUtterances()
+	}
+	if utterances == nil {
+		utterances, err = meetingRepo.ListUtterances(meetingID)
+		if err != nil {
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve utterances: " + err.Error()})
 			return
 		}
-	}(newID, meeting.Transcript.String)
-
-	response := models.PostMeetingResponse{
-		ID: newID,
 	}
-	c.JSON(consts.StatusCreated, response)
+	if utterances == nil {
+		utterances = []models.Utterance{}
+	}
+
+	c.JSON(consts.StatusOK, models.GetUtterancesResponse{Utterances: utterances})
 }
 
 // ListMeetings handles listing all meetings
@@ -180,7 +320,22 @@ func ListMeetings(ctx context.Context, c *app.RequestContext) {
 		return
 	}
 
-	meetings, err := meetingRepo.ListMeetings()
+	// participant_id limits the list to meetings in which that participant spoke
+	var meetings []models.Meeting
+	var err error
+	if c.Query("participant_id") != "" {
+		participantID, ok := queryID(c, "participant_id")
+		if !ok {
+			return
+		}
+		if participantRepo == nil {
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": "Participant repository not initialized"})
+			return
+		}
+		meetings, err = participantRepo.ListMeetingsByParticipant(participantID)
+	} else {
+		meetings, err = meetingRepo.ListMeetings()
+	}
 	if err != nil {
 		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve meetings: " + err.Error()})
 		return
@@ -214,30 +369,65 @@ func GetMeetingSummary(ctx context.Context, c *app.RequestContext) {
 	}
 
 	// Get meeting from repository
-	meeting, err := meetingRepo.GetMeetingByID(meetingID)
-	if err != nil {
-		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve meeting: " + err.Error()})
+	meeting, ok := getMeeting(c, meetingID)
+	if !ok {
 		return
 	}
 
-	// If summary doesn't exist yet, generate it
+	// Without a summary, report the state of its generation so that clients can
+	// tell a pending summary (202) apart from a failed one
+	job := meeting.SummaryJob()
 	if !meeting.SummaryText.Valid || meeting.SummaryText.String == "" {
-		c.JSON(consts.StatusOK, utils.H{
-			"content": "The summary is still being generated. Please try again in a moment.",
-		})
+		switch job.Status {
+		case models.SummaryQueued, models.SummaryRunning:
+			response := utils.H{
+				"status":   job.Status,
+				"attempts": job.Attempts,
+				"content":  "The summary is still being generated. Please try again in a moment.",
+			}
+			if job.Error != "" {
+				// A previous attempt failed and the job is being retried
+				response["last_error"] = job.Error
+			}
+			c.JSON(consts.StatusAccepted, response)
+		case models.SummaryFailed:
+			// The summary model could not produce a summary; retrying is up to the client
+			c.JSON(consts.StatusBadGateway, utils.H{
+				"status":   job.Status,
+				"attempts": job.Attempts,
+				"error":    job.Error,
+			})
+		default:
+			c.JSON(consts.StatusConflict, utils.H{
+				"status": job.Status,
+				"error":  "No summary has been scheduled; live meetings are summarized when they are closed",
+			})
+		}
 		return
 	}
 
 	// Parse tasks from JSON if available todo
 
-	// Construct response JSON
+	// Construct response JSON; meetings summarized before the sections existed get empty ones
 	response := struct {
 		SummaryText    string   `json:"summary"`
 		Tasks          []string `json:"tasks"`
 		TasksStatusNum int64    `json:"tasks_status_num"`
+		Version        int64    `json:"version,omitempty"` // Current summary version
+		models.SummarySections
+		models.SummaryJobStatus
+		models.SummaryOptions
+		Citations *models.SummaryCitations `json:"citations,omitempty"` // Transcript ranges per summary line and item
+		TaskItems []models.TaskItem        `json:"task_items,omitempty"`
 	}{
-		SummaryText:    meeting.SummaryText.String,
-		TasksStatusNum: meeting.TasksStatusNum,
+		SummaryText:      meeting.SummaryText.String,
+		TasksStatusNum:   meeting.TasksStatusNum,
+		Version:          meeting.CurrentSummaryVersion.Int64,
+		SummarySections:  meeting.Sections(),
+		SummaryJobStatus: job,
+		SummaryOptions:   meeting.SummaryOptions(),
+		Citations:        meeting.Citations(),
+		TaskItems:        meeting.TaskItems(),
 	}
 
 	// Parse tasks from JSON
@@ -283,6 +473,26 @@ func HandleChat(ctx context.Context, c *app.RequestContext) {
 		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve meeting: " + err.Error()})
 		return
 	}
+	if meetingInfo == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "Meeting not found"})
+		return
+	}
+
+	// participant_id limits the transcript given to the model to that participant's utterances
+	transcriptLabel := "会议纪要：\n"
+	transcriptText := meetingInfo.Transcript.String
+	if c.Query("participant_id") != "" {
+		participantID, ok := queryID(c, "participant_id")
+		if !ok {
+			return
+		}
+		label, text, err := participantTranscript(meetingID, participantID)
+		if err != nil {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+			return
+		}
+		transcriptLabel, transcriptText = label, text
+	}
 
 	// Set SSE headers
 	c.Response.Header.Set("Content-Type", "text/event-stream")
@@ -292,6 +502,10 @@ func HandleChat(ctx context.Context, c *app.RequestContext) {
 
 	sseStream := sse.NewStream(c)
 
+	// Replace personal information with placeholders before it leaves the server
+	redactor := services.NewMeetingRedactor(meetingInfo)
+	transcriptText = redactor.Redact(transcriptText)
+
 	// Prepare user message for multi-agent
 	msgs := []*schema.Message{
 		{
@@ -300,17 +514,27 @@ func HandleChat(ctx context.Context, c *app.RequestContext) {
 		},
 		{
 			Role:    schema.User,
-			Content: "会议纪要：\n" + meetingInfo.Transcript.String,
+			Content: redactor.Redact(services.FormatMeetingInfo(meetingInfo)),
 		},
 		{
 			Role:    schema.User,
-			Content: "会议总结：\n" + meetingInfo.SummaryText.String,
+			Content: transcriptLabel + transcriptText,
 		},
 		{
 			Role:    schema.User,
-			Content: userMessage,
+			Content: "会议总结：\n" + redactor.Redact(meetingInfo.SummaryText.String),
 		},
+		{
+			Role:    schema.User,
+			Content: redactor.Redact(userMessage),
+		},
+	}
+	// Placeholders in the reply are mapped back only where the config allows it
+	var restoreWith *redact.Redactor
+	if services.RestoreOutput() {
+		restoreWith = redactor
 	}
+	restorer := redact.NewStreamRestorer(restoreWith)
 	// Use global HostMAt from services package
 	hostMA := services.HostMA
 	if hostMA == nil {
@@ -340,24 +564,20 @@ func HandleChat(ctx context.Context, c *app.RequestContext) {
 				if err != nil {
 					if err == io.EOF {
 						log.Println("Multi-agent stream finished.")
+						if rest := restorer.Flush(); rest != "" {
+							publishChatChunk(sseStream, rest)
+						}
 					} else {
 						log.Printf("Error receiving chunk from multi-agent: %v", err)
 						sseStream.Publish(&sse.Event{Event: "error", Data: []byte(`{"error": "Error receiving data from chat service"}`)})
 					}
 					return
 				}
-				res := models.ChatMessage{
-					Data: chunk.Content,
+				content := restorer.Write(chunk.Content)
+				if content == "" && chunk.Content != "" {
+					continue // Held back until a placeholder split across chunks is complete
 				}
-				jsonData, marshalErr := json.Marshal(res)
-				if marshalErr != nil {
-					log.Printf("Error marshalling SSE data: %v", marshalErr)
-					continue
-				}
-				event := &sse.Event{
-					Data: jsonData,
-				}
-				if pubErr := sseStream.Publish(event); pubErr != nil {
+				if pubErr := publishChatChunk(sseStream, content); pubErr != nil {
 					log.Printf("Error publishing SSE event: %v. Client likely disconnected.", pubErr)
 					return
 				}
@@ -368,3 +588,60 @@ func HandleChat(ctx context.Context, c *app.RequestContext) {
 	<-ctx.Done()
 	log.Println("HandleChat request context finished.")
 }
+
+// publishChatChunk sends a piece of the chat reply as an SSE event
+func publishChatChunk(stream *sse.Stream, content string) error {
+	res := models.ChatMessage{
+		Data: content,
+	}
+	jsonData, err := json.Marshal(res)
+	if err != nil {
+		log.Printf("Error marshalling SSE data: %v", err)
+		return nil
+	}
+	return stream.Publish(&sse.Event{
+		Data: jsonData,
+	})
+}
+
+// SetMeetingRedactionLists handles replacing a meeting's redaction allow and deny lists
+func SetMeetingRedactionLists(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+
+	var req models.RedactionListsRequest
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if _, ok := getMeeting(c, meetingID); !ok {
+		return
+	}
+
+	allowJSON, err := json.Marshal(req.Allow)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to encode allow list: " + err.Error()})
+		return
+	}
+	denyJSON, err := json.Marshal(req.Deny)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to encode deny list: " + err.Error()})
+		return
+	}
+	allow := sql.NullString{String: string(allowJSON), Valid: len(req.Allow) > 0}
+	deny := sql.NullString{String: string(denyJSON), Valid: len(req.Deny) > 0}
+	if err := meetingRepo.SetRedactionLists(meetingID, allow, deny); err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to update meeting: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusOK, utils.H{"allow": req.Allow, "deny": req.Deny})
+}
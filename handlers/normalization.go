@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"meetingagent/models"
+	"meetingagent/services"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// SetMeetingNormalization handles replacing a meeting's transcript normalization settings.
+// The utterances are normalized again right away and the summary is regenerated.
+func SetMeetingNormalization(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+
+	var settings models.NormalizationSettings
+	if err := json.Unmarshal(c.Request.Body(), &settings); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if settings.MaxGapSeconds != nil && *settings.MaxGapSeconds < 0 {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "max_gap_seconds cannot be negative"})
+		return
+	}
+
+	meeting, err := meetingRepo.GetMeetingByID(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve meeting: " + err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "Meeting not found"})
+		return
+	}
+
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to encode settings: " + err.Error()})
+		return
+	}
+	meeting.NormalizationJSON = sql.NullString{String: string(settingsJSON), Valid: true}
+	if err := services.NormalizeMeeting(meetingRepo, meeting); err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to normalize transcript: " + err.Error()})
+		return
+	}
+
+	// Live meetings are summarized when they are closed
+	if !meeting.Live {
+		if err := summarizeInBackground(meetingID); err != nil {
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to queue summary: " + err.Error()})
+			return
+		}
+	}
+
+	normalized := meeting.NormalizedUtterances()
+	if normalized == nil {
+		normalized = []models.Utterance{}
+	}
+	c.JSON(consts.StatusOK, models.NormalizationResponse{Settings: settings, Utterances: normalized})
+}
@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"meetingagent/models"
+	"meetingagent/services"
+	"meetingagent/transcript"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+var participantRepo models.ParticipantRepository
+
+// SetParticipantRepository allows setting the participant repository
+func SetParticipantRepository(repo models.ParticipantRepository) {
+	participantRepo = repo
+}
+
+// readParticipantRequest decodes and validates a participant from the request body
+func readParticipantRequest(c *app.RequestContext) (*models.Participant, bool) {
+	var req models.ParticipantRequest
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "Invalid request body: " + err.Error()})
+		return nil, false
+	}
+	req.DisplayName = strings.TrimSpace(req.DisplayName)
+	if req.DisplayName == "" {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "display_name is required"})
+		return nil, false
+	}
+
+	participant := &models.Participant{DisplayName: req.DisplayName}
+	if email := strings.TrimSpace(req.Email); email != "" {
+		participant.Email = sql.NullString{String: email, Valid: true}
+	}
+	var aliases []string
+	for _, alias := range req.Aliases {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	if len(aliases) > 0 {
+		aliasesJSON, err := json.Marshal(aliases)
+		if err != nil {
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to encode aliases: " + err.Error()})
+			return nil, false
+		}
+		participant.AliasesJSON = sql.NullString{String: string(aliasesJSON), Valid: true}
+	}
+	return participant, true
+}
+
+// writeParticipantSaveError reports a failed insert or update, with 409 for a taken display name
+func writeParticipantSaveError(c *app.RequestContext, err error) {
+	if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+		c.JSON(consts.StatusConflict, utils.H{"error": "A participant with this display_name already exists"})
+		return
+	}
+	c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to save participant: " + err.Error()})
+}
+
+// relinkSpeakers matches the speakers of the meetings affected by a participant change,
+// writing an error response if that fails. The participant itself is saved by then.
+func relinkSpeakers(c *app.RequestContext, before, after *models.Participant) bool {
+	if err := services.RelinkParticipantSpeakers(meetingRepo, participantRepo, before, after); err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Participant saved, but failed to link meeting speakers: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+// CreateParticipant handles registering a participant
+func CreateParticipant(ctx context.Context, c *app.RequestContext) {
+	if participantRepo == nil || meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	participant, ok := readParticipantRequest(c)
+	if !ok {
+		return
+	}
+	newID, err := participantRepo.CreateParticipant(participant)
+	if err != nil {
+		writeParticipantSaveError(c, err)
+		return
+	}
+	participant.ID = newID
+	if !relinkSpeakers(c, nil, participant) {
+		return
+	}
+
+	c.JSON(consts.StatusCreated, models.PostParticipantResponse{ID: newID})
+}
+
+// ListParticipants handles listing all registered participants
+func ListParticipants(ctx context.Context, c *app.RequestContext) {
+	if participantRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	participants, err := participantRepo.ListParticipants()
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve participants: " + err.Error()})
+		return
+	}
+	if participants == nil {
+		participants = []models.Participant{}
+	}
+	c.JSON(consts.StatusOK, models.GetParticipantsResponse{Participants: participants})
+}
+
+// UpdateParticipant handles replacing a participant's name, email and aliases
+func UpdateParticipant(ctx context.Context, c *app.RequestContext) {
+	if participantRepo == nil || meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	participantID, ok := queryID(c, "participant_id")
+	if !ok {
+		return
+	}
+	existing, err := participantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve participant: " + err.Error()})
+		return
+	}
+	if existing == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "Participant not found"})
+		return
+	}
+
+	participant, ok := readParticipantRequest(c)
+	if !ok {
+		return
+	}
+	participant.ID = participantID
+	participant.CreatedAt = existing.CreatedAt
+	if err := participantRepo.UpdateParticipant(participantID, participant); err != nil {
+		writeParticipantSaveError(c, err)
+		return
+	}
+	if !relinkSpeakers(c, existing, participant) {
+		return
+	}
+
+	c.JSON(consts.StatusOK, participant)
+}
+
+// GetMeetingSpeakers handles listing a meeting's speakers and the participants they are linked to
+func GetMeetingSpeakers(ctx context.Context, c *app.RequestContext) {
+	if participantRepo == nil || meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+	meeting, err := meetingRepo.GetMeetingByID(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve meeting: " + err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "Meeting not found"})
+		return
+	}
+
+	speakers, err := services.MeetingSpeakers(meetingRepo, participantRepo, meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve speakers: " + err.Error()})
+		return
+	}
+	c.JSON(consts.StatusOK, models.GetMeetingSpeakersResponse{Speakers: speakers})
+}
+
+// SetMeetingSpeakers handles linking a meeting's speakers to participants by hand.
+// Manual links are never replaced by name matching.
+func SetMeetingSpeakers(ctx context.Context, c *app.RequestContext) {
+	if participantRepo == nil || meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+
+	var req models.SetMeetingSpeakersRequest
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if len(req.Links) == 0 {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "links is required"})
+		return
+	}
+
+	meeting, err := meetingRepo.GetMeetingByID(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve meeting: " + err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "Meeting not found"})
+		return
+	}
+
+	for speaker, participantID := range req.Links {
+		link := models.MeetingSpeaker{MeetingID: meetingID, Speaker: strings.TrimSpace(speaker), Manual: true}
+		if participantID != nil {
+			participant, err := participantRepo.GetParticipantByID(*participantID)
+			if err != nil {
+				c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve participant: " + err.Error()})
+				return
+			}
+			if participant == nil {
+				c.JSON(consts.StatusBadRequest, utils.H{"error": fmt.Sprintf("Participant %d not found", *participantID)})
+				return
+			}
+			link.ParticipantID = sql.NullInt64{Int64: *participantID, Valid: true}
+		}
+		if err := participantRepo.SetMeetingSpeaker(link); err != nil {
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to link speaker: " + err.Error()})
+			return
+		}
+	}
+
+	speakers, err := services.MeetingSpeakers(meetingRepo, participantRepo, meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve speakers: " + err.Error()})
+		return
+	}
+	c.JSON(consts.StatusOK, models.GetMeetingSpeakersResponse{Speakers: speakers})
+}
+
+// participantTranscript renders only the utterances a participant spoke in a meeting,
+// returning the label to introduce them with in the chat prompt
+func participantTranscript(meetingID, participantID int64) (string, string, error) {
+	if participantRepo == nil {
+		return "", "", fmt.Errorf("participant repository not initialized")
+	}
+	participant, err := participantRepo.GetParticipantByID(participantID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to retrieve participant: %w", err)
+	}
+	if participant == nil {
+		return "", "", fmt.Errorf("participant %d not found", participantID)
+	}
+	names, err := services.ParticipantSpeakerNames(participantRepo, meetingID, participantID)
+	if err != nil {
+		return "", "", err
+	}
+	if len(names) == 0 {
+		return "", "", fmt.Errorf("participant %s did not speak in this meeting", participant.DisplayName)
+	}
+
+	utterances, err := meetingRepo.ListUtterances(meetingID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to retrieve utterances: %w", err)
+	}
+	spokenBy := make(map[string]bool, len(names))
+	for _, name := range names {
+		spokenBy[name] = true
+	}
+	var spoken []models.Utterance
+	for _, u := range utterances {
+		if spokenBy[strings.TrimSpace(u.Speaker)] {
+			spoken = append(spoken, u)
+		}
+	}
+	return "会议纪要（仅 " + participant.DisplayName + " 的发言）：\n", transcript.Render(spoken), nil
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"meetingagent/config"
+	"meetingagent/models"
+	"meetingagent/services"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// RegenerateMeetingSummary handles regenerating a meeting's summary and tasks, optionally
+// with a named template and additional instructions. The options are kept for later
+// regenerations; an empty body switches back to the default system message.
+func RegenerateMeetingSummary(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+
+	var options models.SummaryOptions
+	if body := c.Request.Body(); len(body) > 0 {
+		if err := json.Unmarshal(body, &options); err != nil {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": "Invalid request body: " + err.Error()})
+			return
+		}
+	}
+	options.Template = strings.TrimSpace(options.Template)
+	options.Instructions = strings.TrimSpace(options.Instructions)
+	if _, ok := config.AppConfig.GetSummaryTemplateMessage(options.Template); !ok {
+		c.JSON(consts.StatusBadRequest, utils.H{
+			"error":     "Unknown summary template: " + options.Template,
+			"templates": config.AppConfig.SummaryTemplateNames(),
+		})
+		return
+	}
+
+	meeting, ok := getMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+	if meeting.Live {
+		c.JSON(consts.StatusConflict, utils.H{"error": "Live meetings are summarized when they are closed"})
+		return
+	}
+	if !meeting.Transcript.Valid {
+		c.JSON(consts.StatusConflict, utils.H{"error": "The meeting has not been transcribed yet"})
+		return
+	}
+
+	optionsJSON, err := json.Marshal(options)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to encode summary options: " + err.Error()})
+		return
+	}
+	if err := meetingRepo.SetSummaryOptions(meetingID, sql.NullString{String: string(optionsJSON), Valid: true}); err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to store summary options: " + err.Error()})
+		return
+	}
+
+	if err := summarizeInBackground(meetingID); err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to queue summary: " + err.Error()})
+		return
+	}
+
+	c.JSON(consts.StatusAccepted, models.PostMeetingResponse{ID: meetingID})
+}
+
+// ListSummaryTemplates handles listing the names of the summary templates
+func ListSummaryTemplates(ctx context.Context, c *app.RequestContext) {
+	c.JSON(consts.StatusOK, models.GetSummaryTemplatesResponse{Templates: config.AppConfig.SummaryTemplateNames()})
+}
+
+// ListSummaryVersions handles listing the summary versions of a meeting, newest first
+func ListSummaryVersions(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+	meeting, ok := getMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+
+	versions, err := meetingRepo.ListSummaryVersions(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve summary versions: " + err.Error()})
+		return
+	}
+
+	response := models.GetSummaryVersionsResponse{Versions: make([]models.SummaryVersionResponse, 0, len(versions))}
+	for i := range versions {
+		v := &versions[i]
+		response.Versions = append(response.Versions, models.SummaryVersionResponse{
+			Version:      v.Version,
+			Current:      meeting.CurrentSummaryVersion.Valid && meeting.CurrentSummaryVersion.Int64 == int64(v.Version),
+			Model:        v.Model.String,
+			Template:     v.Template.String,
+			Instructions: v.Instructions.String,
+			Usage:        v.Usage,
+			CreatedAt:    v.CreatedAt,
+			SummaryResponse: models.SummaryResponse{
+				Summary:         v.SummaryText,
+				Tasks:           nonNilStrings(v.Tasks()),
+				SummarySections: v.Sections(),
+				Citations:       v.Citations(),
+				TaskItems:       v.TaskItems(),
+			},
+		})
+	}
+	c.JSON(consts.StatusOK, response)
+}
+
+// PinSummaryVersion handles making a stored summary version the meeting's current summary.
+// Completed tasks stay completed where the pinned version has the same task.
+func PinSummaryVersion(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+	versionNum, ok := queryID(c, "version")
+	if !ok {
+		return
+	}
+	meeting, ok := getMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+	version, ok := getSummaryVersion(c, meetingID, versionNum)
+	if !ok {
+		return
+	}
+
+	if err := services.PinSummaryVersion(meetingRepo, meeting, version); err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to pin summary version: " + err.Error()})
+		return
+	}
+	c.JSON(consts.StatusOK, utils.H{"id": meetingID, "version": version.Version})
+}
+
+// DiffSummaryVersions handles comparing two summary versions of a meeting
+func DiffSummaryVersions(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+	fromNum, ok := queryID(c, "from")
+	if !ok {
+		return
+	}
+	toNum, ok := queryID(c, "to")
+	if !ok {
+		return
+	}
+	from, ok := getSummaryVersion(c, meetingID, fromNum)
+	if !ok {
+		return
+	}
+	to, ok := getSummaryVersion(c, meetingID, toNum)
+	if !ok {
+		return
+	}
+
+	c.JSON(consts.StatusOK, services.DiffSummaryVersions(from, to))
+}
+
+// getSummaryVersion loads a summary version of a meeting, writing an error response if it doesn't exist
+func getSummaryVersion(c *app.RequestContext, meetingID int64, versionNum int64) (*models.SummaryVersion, bool) {
+	version, err := meetingRepo.GetSummaryVersion(meetingID, int(versionNum))
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve summary version: " + err.Error()})
+		return nil, false
+	}
+	if version == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": fmt.Sprintf("Summary version %d not found", versionNum)})
+		return nil, false
+	}
+	return version, true
+}
+
+func nonNilStrings(list []string) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// GetMeetingChapters handles retrieving the chapters of a meeting. They are generated
+// with the summary, so the summary job state tells whether they are still coming.
+func GetMeetingChapters(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+	meeting, ok := getMeeting(c, meetingID)
+	if !ok {
+		return
+	}
+
+	c.JSON(consts.StatusOK, models.GetChaptersResponse{
+		Chapters:         meeting.Chapters(),
+		SummaryJobStatus: meeting.SummaryJob(),
+	})
+}
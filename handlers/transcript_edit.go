@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"meetingagent/models"
+	"meetingagent/transcript"
+
+	"github.com/cloudwego/hertz/pkg/app"
+	"github.com/cloudwego/hertz/pkg/common/utils"
+	"github.com/cloudwego/hertz/pkg/protocol/consts"
+)
+
+// UpdateMeetingTranscript handles correcting a meeting's transcript, either as a whole
+// or per utterance. The previous version is kept as a revision and the summary and
+// tasks are regenerated.
+func UpdateMeetingTranscript(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+
+	var req models.UpdateTranscriptRequest
+	if err := json.Unmarshal(c.Request.Body(), &req); err != nil {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+	if req.Transcript != nil && len(req.Utterances) > 0 {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "transcript and utterances cannot be edited in the same request"})
+		return
+	}
+	if req.Transcript == nil && len(req.Utterances) == 0 && len(req.Speakers) == 0 {
+		c.JSON(consts.StatusBadRequest, utils.H{"error": "One of transcript, utterances or speakers is required"})
+		return
+	}
+
+	meeting, err := meetingRepo.GetMeetingByID(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve meeting: " + err.Error()})
+		return
+	}
+	if meeting == nil {
+		c.JSON(consts.StatusNotFound, utils.H{"error": "Meeting not found"})
+		return
+	}
+
+	var text string
+	var utterances []models.Utterance
+	if req.Transcript != nil {
+		// Whole replacement, parsed like an upload with the original filename as format hint.
+		// Edited text is never in a binary format such as .docx, so those names give no hint.
+		hint := meeting.AudioFilename
+		if strings.EqualFold(filepath.Ext(hint), ".docx") {
+			hint = ""
+		}
+		doc, err := transcript.Parse(hint, "", []byte(*req.Transcript))
+		if err != nil {
+			writeParseError(c, err)
+			return
+		}
+		text, utterances = doc.Text, doc.Utterances
+	} else {
+		text = meeting.Transcript.String
+		utterances, err = meetingRepo.ListUtterances(meetingID)
+		if err != nil {
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve utterances: " + err.Error()})
+			return
+		}
+	}
+
+	if len(req.Utterances) > 0 || len(req.Speakers) > 0 {
+		if len(utterances) == 0 {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": "Meeting has no parsed utterances to edit"})
+			return
+		}
+		if err := applyUtteranceEdits(utterances, req.Utterances, req.Speakers); err != nil {
+			c.JSON(consts.StatusBadRequest, utils.H{"error": err.Error()})
+			return
+		}
+		// Per-utterance edits can't be mapped back onto arbitrary source formats, so the
+		// transcript text is re-rendered from the corrected utterances
+		text = transcript.Render(utterances)
+	}
+
+	revisionID, err := meetingRepo.UpdateTranscript(meetingID, text, utterances)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to update transcript: " + err.Error()})
+		return
+	}
+
+	// Live meetings are summarized when they are closed
+	if !meeting.Live {
+		if err := summarizeInBackground(meetingID); err != nil {
+			c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to queue summary: " + err.Error()})
+			return
+		}
+	}
+
+	c.JSON(consts.StatusOK, models.UpdateTranscriptResponse{ID: meetingID, RevisionID: revisionID})
+}
+
+// applyUtteranceEdits applies per-utterance corrections and then speaker renames in place
+func applyUtteranceEdits(utterances []models.Utterance, edits []models.UtteranceEdit, speakers map[string]string) error {
+	bySeq := make(map[int]*models.Utterance, len(utterances))
+	for i := range utterances {
+		bySeq[utterances[i].Seq] = &utterances[i]
+	}
+
+	for _, edit := range edits {
+		u, ok := bySeq[edit.Seq]
+		if !ok {
+			return fmt.Errorf("utterance %d does not exist", edit.Seq)
+		}
+		if edit.Speaker != nil {
+			u.Speaker = strings.TrimSpace(*edit.Speaker)
+		}
+		if edit.Text != nil {
+			text := strings.TrimSpace(*edit.Text)
+			if text == "" {
+				return fmt.Errorf("utterance %d: text cannot be empty", edit.Seq)
+			}
+			u.Text = text
+		}
+	}
+
+	for i := range utterances {
+		if renamed, ok := speakers[utterances[i].Speaker]; ok {
+			utterances[i].Speaker = strings.TrimSpace(renamed)
+		}
+	}
+	return nil
+}
+
+// ListTranscriptRevisions handles listing the earlier versions of a meeting's transcript
+func ListTranscriptRevisions(ctx context.Context, c *app.RequestContext) {
+	if meetingRepo == nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Repository not initialized"})
+		return
+	}
+
+	meetingID, ok := queryMeetingID(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := meetingRepo.ListTranscriptRevisions(meetingID)
+	if err != nil {
+		c.JSON(consts.StatusInternalServerError, utils.H{"error": "Failed to retrieve revisions: " + err.Error()})
+		return
+	}
+	if revisions == nil {
+		revisions = []models.TranscriptRevision{}
+	}
+
+	c.JSON(consts.StatusOK, models.GetTranscriptRevisionsResponse{Revisions: revisions})
+}
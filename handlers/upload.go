@@ -0,0 +1,246 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"meetingagent/config"
+	"meetingagent/models"
+
+	"github.com/cloudwego/hertz/pkg/app"
+)
+
+// meetingUpload is a transcript upload along with its optional metadata,
+// read either from a raw request body or from a multipart form
+type meetingUpload struct {
+	meetingMetadata
+	FileName    string
+	ContentType string
+	Body        []byte
+	OnDuplicate string // duplicateExisting or duplicateReject
+}
+
+// meetingMetadata is the optional descriptive information given when creating a meeting
+type meetingMetadata struct {
+	Title        string
+	Description  string
+	Remark       string
+	Participants []string
+	ScheduledAt  time.Time // Zero if not given
+}
+
+// apply copies the metadata that was given onto the meeting
+func (md *meetingMetadata) apply(meeting *models.Meeting) error {
+	if md.Title != "" {
+		meeting.Title = sql.NullString{String: md.Title, Valid: true}
+	}
+	if md.Description != "" {
+		meeting.Description = sql.NullString{String: md.Description, Valid: true}
+	}
+	if md.Remark != "" {
+		meeting.Remark = sql.NullString{String: md.Remark, Valid: true}
+	}
+	if !md.ScheduledAt.IsZero() {
+		meeting.ScheduledAt = sql.NullTime{Time: md.ScheduledAt, Valid: true}
+	}
+	if len(md.Participants) > 0 {
+		participantsJSON, err := json.Marshal(md.Participants)
+		if err != nil {
+			return fmt.Errorf("failed to encode participants: %w", err)
+		}
+		meeting.ParticipantsJSON = sql.NullString{String: string(participantsJSON), Valid: true}
+	}
+	return nil
+}
+
+// on_duplicate options deciding what happens when the same transcript is uploaded again
+const (
+	duplicateExisting = "existing" // Return the ID of the meeting that already has this content
+	duplicateReject   = "reject"   // Fail with 409 Conflict naming the existing meeting
+)
+
+// readMeetingUpload reads the uploaded transcript from the request. Multipart
+// forms carry the file in the "file" part (or the first file part) and the
+// metadata as form fields; any other request is treated as a raw body named
+// by the X-File-Name header, with the metadata in query parameters.
+func readMeetingUpload(c *app.RequestContext) (*meetingUpload, error) {
+	if !strings.HasPrefix(string(c.ContentType()), "multipart/form-data") {
+		body, err := c.Body()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+		upload := &meetingUpload{
+			FileName:    string(c.GetHeader("X-File-Name")),
+			ContentType: string(c.ContentType()),
+			Body:        body,
+		}
+		query := make(map[string][]string)
+		c.QueryArgs().VisitAll(func(key, value []byte) {
+			query[string(key)] = append(query[string(key)], string(value))
+		})
+		if err := upload.readMetadata(query); err != nil {
+			return nil, err
+		}
+		return upload, nil
+	}
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, fmt.Errorf("invalid multipart form: %w", err)
+	}
+
+	files := form.File["file"]
+	if len(files) == 0 {
+		for _, fhs := range form.File {
+			if len(fhs) > 0 {
+				files = fhs
+				break
+			}
+		}
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("multipart form has no file part")
+	}
+
+	fh := files[0]
+	f, err := fh.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open uploaded file: %w", err)
+	}
+	defer f.Close()
+	body, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uploaded file: %w", err)
+	}
+
+	upload := &meetingUpload{
+		FileName:    fh.Filename,
+		ContentType: fh.Header.Get("Content-Type"),
+		Body:        body,
+	}
+	if err := upload.readMetadata(form.Value); err != nil {
+		return nil, err
+	}
+	return upload, nil
+}
+
+// readMetadata fills the optional meeting metadata and upload options from form fields or query parameters
+func (u *meetingUpload) readMetadata(values map[string][]string) error {
+	u.OnDuplicate = strings.TrimSpace(formValue(values, "on_duplicate"))
+	switch u.OnDuplicate {
+	case "":
+		u.OnDuplicate = duplicateExisting
+	case duplicateExisting, duplicateReject:
+	default:
+		return fmt.Errorf("invalid on_duplicate %q, expected %q or %q", u.OnDuplicate, duplicateExisting, duplicateReject)
+	}
+
+	u.Title = strings.TrimSpace(formValue(values, "title"))
+	u.Description = strings.TrimSpace(formValue(values, "description"))
+	u.Remark = strings.TrimSpace(formValue(values, "remark"))
+
+	participants, err := parseParticipants(values["participants"])
+	if err != nil {
+		return err
+	}
+	u.Participants = participants
+
+	if v := strings.TrimSpace(formValue(values, "scheduled_at")); v != "" {
+		scheduledAt, err := parseScheduledAt(v)
+		if err != nil {
+			return err
+		}
+		u.ScheduledAt = scheduledAt
+	}
+	return nil
+}
+
+// scheduledAtLayouts are the accepted scheduled_at formats; layouts without a zone use local time
+var scheduledAtLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+func parseScheduledAt(v string) (time.Time, error) {
+	for _, layout := range scheduledAtLayouts {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid scheduled_at %q, expected RFC 3339 or YYYY-MM-DD [HH:MM]", v)
+}
+
+func formValue(values map[string][]string, key string) string {
+	if v := values[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// parseParticipants accepts repeated fields, comma separated lists and JSON arrays
+func parseParticipants(values []string) ([]string, error) {
+	var participants []string
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if strings.HasPrefix(v, "[") {
+			var list []string
+			if err := json.Unmarshal([]byte(v), &list); err != nil {
+				return nil, fmt.Errorf("invalid participants list: %w", err)
+			}
+			for _, p := range list {
+				if p = strings.TrimSpace(p); p != "" {
+					participants = append(participants, p)
+				}
+			}
+			continue
+		}
+		for _, p := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == '\n' }) {
+			if p = strings.TrimSpace(p); p != "" {
+				participants = append(participants, p)
+			}
+		}
+	}
+	return participants, nil
+}
+
+// audioExtensions are the file extensions treated as audio recordings
+var audioExtensions = map[string]bool{
+	".mp3": true, ".wav": true, ".m4a": true, ".aac": true, ".ogg": true,
+	".oga": true, ".opus": true, ".flac": true, ".webm": true, ".amr": true,
+}
+
+// isAudioUpload reports whether the upload is an audio recording rather than a transcript
+func isAudioUpload(u *meetingUpload) bool {
+	return strings.HasPrefix(strings.ToLower(u.ContentType), "audio/") ||
+		audioExtensions[strings.ToLower(filepath.Ext(u.FileName))]
+}
+
+var unsafeFileNameChars = regexp.MustCompile(`[^\p{L}\p{N}._-]+`)
+
+// saveAudio writes an uploaded recording to the configured audio directory and returns its path
+func saveAudio(u *meetingUpload) (string, error) {
+	dir := config.AppConfig.Transcription.AudioDir
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create audio directory: %w", err)
+	}
+
+	base := unsafeFileNameChars.ReplaceAllString(filepath.Base(u.FileName), "_")
+	if base == "" || base == "." || base == "_" {
+		base = "audio"
+	}
+	path := filepath.Join(dir, time.Now().Format("20060102150405.000000000")+"_"+base)
+	if err := os.WriteFile(path, u.Body, 0o644); err != nil {
+		return "", fmt.Errorf("failed to write audio file: %w", err)
+	}
+	return path, nil
+}
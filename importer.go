@@ -0,0 +1,252 @@
+package main
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"meetingagent/models"
+	"meetingagent/services"
+	"meetingagent/transcript"
+)
+
+// importExtensions are the file extensions picked up by the import command
+var importExtensions = map[string]bool{
+	".json": true, ".txt": true, ".text": true, ".md": true, ".vtt": true, ".srt": true, ".docx": true,
+}
+
+// importFile is a transcript found in a directory or zip archive
+type importFile struct {
+	path string
+	read func() ([]byte, error)
+}
+
+// importOutcome is what happened to a single file
+type importOutcome string
+
+const (
+	outcomeImported importOutcome = "imported"
+	outcomeSkipped  importOutcome = "skipped"
+	outcomeResumed  importOutcome = "resumed" // Already imported, but its summary is missing
+	outcomeFailed   importOutcome = "failed"
+)
+
+// runImport implements "meetingagent import [flags] <directory|archive.zip>". Files whose
+// content was imported before are skipped, so an interrupted import can simply be rerun.
+func runImport(args []string) int {
+	flags := flag.NewFlagSet("import", flag.ContinueOnError)
+	concurrency := flags.Int("concurrency", 2, "number of meetings summarized at the same time")
+	noSummary := flags.Bool("no-summary", false, "import transcripts without generating summaries")
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "Usage: %s import [flags] <directory|archive.zip>\n", filepath.Base(os.Args[0]))
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 || *concurrency < 1 {
+		flags.Usage()
+		return 2
+	}
+
+	cfg := setup()
+	defer closeDatabase()
+
+	files, closeSource, err := collectImportFiles(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to read %s: %v\n", flags.Arg(0), err)
+		return 1
+	}
+	defer closeSource()
+
+	report := func(format string, a ...any) {
+		fmt.Printf(format+"\n", a...)
+	}
+
+	var summaryFailures int
+	var queued []int64
+	counts := make(map[importOutcome]int)
+	for _, f := range files {
+		outcome, meeting, detail := importTranscript(f)
+		counts[outcome]++
+		switch outcome {
+		case outcomeFailed:
+			report("[%s] %s: %s", outcome, f.path, detail)
+			continue
+		case outcomeSkipped:
+			report("[%s] %s: already imported as meeting %d", outcome, f.path, meeting)
+			continue
+		default:
+			report("[%s] %s -> meeting %d (%s)", outcome, f.path, meeting, detail)
+		}
+		if !*noSummary {
+			if err := jobs.Enqueue(models.JobSummarize, meeting); err != nil {
+				report("[summary failed] meeting %d: %v", meeting, err)
+				summaryFailures++
+				continue
+			}
+			queued = append(queued, meeting)
+		}
+	}
+
+	// Summaries are generated through the job queue, so a server sharing the database
+	// may pick some of them up as well; the import waits until all jobs are done
+	if len(queued) > 0 {
+		jobsCfg := cfg.Jobs
+		jobsCfg.Workers = *concurrency
+		if err := services.NewJobQueue(repo, repo, repo, jobsCfg).Drain(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to run summary jobs: %v\n", err)
+			return 1
+		}
+	}
+	for _, meetingID := range queued {
+		meeting, err := repo.GetMeetingByID(meetingID)
+		switch {
+		case err != nil:
+			report("[summary failed] meeting %d: %v", meetingID, err)
+		case meeting.SummaryJob().Status != models.SummarySucceeded:
+			report("[summary failed] meeting %d: %s", meetingID, meeting.SummaryError.String)
+		default:
+			report("[summarized] meeting %d", meetingID)
+			continue
+		}
+		summaryFailures++
+	}
+
+	fmt.Printf("\n%d files: %d imported, %d resumed, %d skipped, %d failed; %d summaries failed\n",
+		len(files), counts[outcomeImported], counts[outcomeResumed], counts[outcomeSkipped], counts[outcomeFailed], summaryFailures)
+	if counts[outcomeFailed] > 0 || summaryFailures > 0 {
+		return 1
+	}
+	return 0
+}
+
+// importTranscript creates a meeting from one file. It returns the outcome, the
+// meeting ID (if any) and a detail for the report.
+func importTranscript(f importFile) (importOutcome, int64, string) {
+	data, err := f.read()
+	if err != nil {
+		return outcomeFailed, 0, err.Error()
+	}
+	fileName := filepath.Base(f.path)
+
+	doc, err := transcript.Parse(fileName, "", data)
+	if err != nil {
+		return outcomeFailed, 0, err.Error()
+	}
+
+	contentHash := models.ContentHash(data)
+	existing, err := repo.GetMeetingByContentHash(contentHash)
+	if err != nil {
+		return outcomeFailed, 0, err.Error()
+	}
+	if existing != nil {
+		if existing.SummaryText.Valid && existing.SummaryText.String != "" {
+			return outcomeSkipped, existing.ID, ""
+		}
+		// Meetings imported before they were created atomically may lack their utterances
+		if len(doc.Utterances) > 0 {
+			stored, err := repo.ListUtterances(existing.ID)
+			if err != nil {
+				return outcomeFailed, existing.ID, err.Error()
+			}
+			if len(stored) == 0 {
+				if err := repo.SaveUtterances(existing.ID, doc.Utterances); err != nil {
+					return outcomeFailed, existing.ID, err.Error()
+				}
+			}
+		}
+		return outcomeResumed, existing.ID, "summary missing"
+	}
+
+	currentTime := time.Now()
+	meeting := &models.Meeting{
+		Name:          fileName,
+		AudioFilename: fileName,
+		Transcript:    sql.NullString{String: doc.Text, Valid: true},
+		ContentHash:   sql.NullString{String: contentHash, Valid: true},
+		Remark:        sql.NullString{String: "Imported from " + f.path, Valid: true},
+		UploadedAt:    currentTime,
+		ModifiedAt:    currentTime,
+	}
+	newID, err := services.CreateMeetingWithUniqueName(repo, meeting, doc.Utterances)
+	if err != nil {
+		return outcomeFailed, 0, err.Error()
+	}
+	return outcomeImported, newID, fmt.Sprintf("%s, %d utterances", doc.Format, len(doc.Utterances))
+}
+
+// collectImportFiles lists the transcripts in a directory tree or zip archive
+func collectImportFiles(source string) ([]importFile, func(), error) {
+	info, err := os.Stat(source)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !info.IsDir() {
+		if strings.ToLower(filepath.Ext(source)) != ".zip" {
+			return nil, nil, fmt.Errorf("expected a directory or .zip archive")
+		}
+		archive, err := zip.OpenReader(source)
+		if err != nil {
+			return nil, nil, err
+		}
+		var files []importFile
+		for _, zf := range archive.File {
+			if zf.FileInfo().IsDir() || !isImportable(zf.Name) {
+				continue
+			}
+			zf := zf
+			files = append(files, importFile{
+				path: source + "!" + zf.Name,
+				read: func() ([]byte, error) {
+					rc, err := zf.Open()
+					if err != nil {
+						return nil, err
+					}
+					defer rc.Close()
+					return io.ReadAll(rc)
+				},
+			})
+		}
+		return files, func() { archive.Close() }, nil
+	}
+
+	var files []importFile
+	err = filepath.WalkDir(source, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != source && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isImportable(path) {
+			files = append(files, importFile{path: path, read: func() ([]byte, error) { return os.ReadFile(path) }})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, func() {}, nil
+}
+
+// isImportable skips hidden files, macOS archive metadata and unsupported extensions
+func isImportable(path string) bool {
+	base := filepath.Base(path)
+	if strings.HasPrefix(base, ".") || strings.HasPrefix(path, "__MACOSX/") {
+		return false
+	}
+	return importExtensions[strings.ToLower(filepath.Ext(base))]
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"log"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -22,6 +23,74 @@ const dbFile = "meetings.db"    // Define database file name
 const configFile = "config.yml" // Define config file name
 
 func main() {
+	// Subcommands share the configuration and database of the server
+	if len(os.Args) > 1 && os.Args[1] == "import" {
+		os.Exit(runImport(os.Args[2:]))
+	}
+
+	cfg := setup()
+	defer closeDatabase()
+
+	// Workers stop with the server; jobs they leave unfinished are resumed at the next start
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	if err := jobs.Start(jobsCtx); err != nil {
+		log.Fatalf("Failed to start job queue: %v", err)
+	}
+
+	h := server.Default(server.WithMaxRequestBodySize(cfg.MaxUploadMB << 20))
+	h.Use(Logger())
+
+	// Register API routes first
+	h.POST("/meeting", handlers.CreateMeeting)
+	h.GET("/meeting", handlers.ListMeetings)
+	h.POST("/meeting/live", handlers.CreateLiveMeeting)
+	h.POST("/meeting/utterances", handlers.AppendUtterances)
+	h.POST("/meeting/close", handlers.CloseLiveMeeting)
+	h.GET("/meeting/ws", handlers.LiveMeetingSocket)
+	h.GET("/summary", handlers.GetMeetingSummary)
+	h.POST("/summary/regenerate", handlers.RegenerateMeetingSummary)
+	h.GET("/summary/templates", handlers.ListSummaryTemplates)
+	h.GET("/summary/versions", handlers.ListSummaryVersions)
+	h.PUT("/summary/versions/current", handlers.PinSummaryVersion)
+	h.GET("/summary/versions/diff", handlers.DiffSummaryVersions)
+	h.GET("/tasks", handlers.GetMeetingTasks)
+	h.GET("/utterances", handlers.GetMeetingUtterances)
+	h.GET("/chapters", handlers.GetMeetingChapters)
+	h.PUT("/meeting/transcript", handlers.UpdateMeetingTranscript)
+	h.GET("/meeting/transcript/revisions", handlers.ListTranscriptRevisions)
+	h.PUT("/meeting/redaction", handlers.SetMeetingRedactionLists)
+	h.PUT("/meeting/normalization", handlers.SetMeetingNormalization)
+	h.GET("/meeting/speakers", handlers.GetMeetingSpeakers)
+	h.PUT("/meeting/speakers", handlers.SetMeetingSpeakers)
+	h.POST("/participants", handlers.CreateParticipant)
+	h.GET("/participants", handlers.ListParticipants)
+	h.PUT("/participants", handlers.UpdateParticipant)
+	h.GET("/chat", handlers.HandleChat)
+
+	// Serve static files
+	h.StaticFS("/", &app.FS{
+		Root:               "./static",
+		PathRewrite:        app.NewPathSlashesStripper(1),
+		IndexNames:         []string{"index.html"},
+		GenerateIndexPages: true,
+	})
+
+	// Start server
+	h.Spin()
+}
+
+// db is the database opened by setup
+var db *sql.DB
+
+// repo is the repository over db created by setup
+var repo *database.SQLiteRepository
+
+// jobs is the background job queue over repo created by setup
+var jobs *services.JobQueue
+
+// setup loads the configuration, initializes the services and opens the database
+func setup() *config.Config {
 	// --- Configuration Setup ---
 	configPath := filepath.Join(".", configFile)
 	cfg, err := config.LoadConfig(configPath)
@@ -32,11 +101,10 @@ func main() {
 	services.Init()
 
 	// --- Database Setup ---
-	db, err := sql.Open("sqlite3", dbFile+"?_foreign_keys=on") // Enable foreign keys if needed later
+	db, err = sql.Open("sqlite3", dbFile+"?_foreign_keys=on") // Enable foreign keys if needed later
 	if err != nil {
 		log.Fatalf("Failed to open database: %v", err)
 	}
-	defer db.Close()
 
 	// Ping the database to ensure connection is valid
 	if err := db.Ping(); err != nil {
@@ -49,32 +117,23 @@ func main() {
 	}
 
 	// Create repository instance
-	repo := database.NewSQLiteRepository(db)
+	repo = database.NewSQLiteRepository(db)
+
+	jobs = services.NewJobQueue(repo, repo, repo, cfg.Jobs)
 
 	// Inject repository into handlers
 	handlers.SetMeetingRepository(repo)
+	handlers.SetParticipantRepository(repo)
+	handlers.SetJobQueue(jobs)
 	// --- End Database Setup ---
 
-	h := server.Default()
-	h.Use(Logger())
-
-	// Register API routes first
-	h.POST("/meeting", handlers.CreateMeeting)
-	h.GET("/meeting", handlers.ListMeetings)
-	h.GET("/summary", handlers.GetMeetingSummary)
-	h.GET("/tasks", handlers.GetMeetingTasks)
-	h.GET("/chat", handlers.HandleChat)
-
-	// Serve static files
-	h.StaticFS("/", &app.FS{
-		Root:               "./static",
-		PathRewrite:        app.NewPathSlashesStripper(1),
-		IndexNames:         []string{"index.html"},
-		GenerateIndexPages: true,
-	})
+	return cfg
+}
 
-	// Start server
-	h.Spin()
+func closeDatabase() {
+	if db != nil {
+		db.Close()
+	}
 }
 
 func Logger() app.HandlerFunc {
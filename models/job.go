@@ -0,0 +1,52 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Job kinds
+const (
+	JobTranscribe = "transcribe" // Transcribe the meeting's stored audio, then queue its summary
+	JobSummarize  = "summarize"  // Generate the meeting's summary and tasks
+)
+
+// Job states
+const (
+	JobQueued    = "queued"
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+// Job is a unit of background work on a meeting, stored so that it survives restarts
+type Job struct {
+	ID          int64          `json:"id"`
+	Kind        string         `json:"kind"`
+	MeetingID   int64          `json:"meeting_id"`
+	Status      string         `json:"status"`
+	Attempts    int            `json:"attempts"`
+	MaxAttempts int            `json:"max_attempts"`
+	LastError   sql.NullString `json:"last_error,omitempty"`
+	RunAt       time.Time      `json:"run_at"` // Earliest time the job may be started
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+}
+
+// JobRepository defines the interface for job queue operations
+type JobRepository interface {
+	// EnqueueJob adds a job, or returns the ID of an identical job that is still queued
+	EnqueueJob(job *Job) (int64, error)
+	// ClaimJob marks the next due queued job as running and counts the attempt; it returns nil if none is due
+	ClaimJob(now time.Time) (*Job, error)
+	CompleteJob(id int64) error
+	RetryJob(id int64, runAt time.Time, errMsg string) error
+	FailJob(id int64, errMsg string) error
+	// TouchJob records that a running job is still alive
+	TouchJob(id int64) error
+	// RequeueRunningJobs queues the running jobs not touched since staleBefore again and returns them
+	RequeueRunningJobs(staleBefore time.Time) ([]Job, error)
+	// NextJobRunAt returns when the earliest queued job is due, or nil if there is none
+	NextJobRunAt() (*time.Time, error)
+	CountPendingJobs() (int, error)
+}
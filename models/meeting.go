@@ -1,33 +1,230 @@
 package models
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"time"
 )
 
 // Meeting represents a meeting entity in the database
 type Meeting struct {
-	ID             int64          `json:"id"`
-	Name           string         `json:"name"` // Unique name, default to uploaded filename
-	Transcript     sql.NullString `json:"transcript,omitempty"`
-	SummaryText    sql.NullString `json:"summary_text,omitempty"`    // Store only meeting summary content
-	TasksJSON      sql.NullString `json:"tasks_json,omitempty"`      // Store tasks as JSON string array
-	TasksStatusNum int64          `json:"tasks_status_num"`          // Store task status using binary flags
-	ChatHistory    sql.NullString `json:"chat_history,omitempty"`    // Store as JSON string
-	Remark         sql.NullString `json:"remark,omitempty"`
-	AudioFilename  string         `json:"audio_filename"` // Original uploaded audio/text filename
-	UploadedAt     time.Time      `json:"uploaded_at"`
-	ModifiedAt     time.Time      `json:"modified_at"`
-	DeletedAt      sql.NullTime   `json:"-"` // Use '-' to exclude from default JSON responses
+	ID                       int64          `json:"id"`
+	Name                     string         `json:"name"` // Unique name, default to uploaded filename
+	Transcript               sql.NullString `json:"transcript,omitempty"`
+	SummaryText              sql.NullString `json:"summary_text,omitempty"` // Store only meeting summary content
+	TasksJSON                sql.NullString `json:"tasks_json,omitempty"`   // Store tasks as JSON string array
+	TasksStatusNum           int64          `json:"tasks_status_num"`       // Store task status using binary flags
+	ChatHistory              sql.NullString `json:"chat_history,omitempty"` // Store as JSON string
+	Remark                   sql.NullString `json:"remark,omitempty"`
+	AudioFilename            string         `json:"audio_filename"`              // Original uploaded audio/text filename
+	ParticipantsJSON         sql.NullString `json:"participants_json,omitempty"` // Store participants as JSON string array
+	Title                    sql.NullString `json:"title,omitempty"`
+	Description              sql.NullString `json:"description,omitempty"`
+	ScheduledAt              sql.NullTime   `json:"scheduled_at,omitempty"`               // When the meeting took place, if known
+	ContentHash              sql.NullString `json:"content_hash,omitempty"`               // SHA-256 of the uploaded content, used to detect re-uploads
+	AudioPath                sql.NullString `json:"audio_path,omitempty"`                 // Where an uploaded recording is stored on disk
+	Live                     bool           `json:"live"`                                 // Meeting is in progress and still accepting utterances
+	RedactAllowJSON          sql.NullString `json:"redact_allow_json,omitempty"`          // Store values never redacted for this meeting as JSON string array
+	RedactDenyJSON           sql.NullString `json:"redact_deny_json,omitempty"`           // Store terms always redacted for this meeting as JSON string array
+	NormalizationJSON        sql.NullString `json:"normalization_json,omitempty"`         // Store NormalizationSettings as JSON
+	NormalizedUtterancesJSON sql.NullString `json:"normalized_utterances_json,omitempty"` // Store the normalized utterances given to the summary model as JSON array
+	SectionsJSON             sql.NullString `json:"sections_json,omitempty"`              // Store SummarySections as JSON
+	SummaryStatus            sql.NullString `json:"summary_status,omitempty"`             // One of the Summary* job states; unset until a summary is first scheduled
+	SummaryError             sql.NullString `json:"summary_error,omitempty"`              // Why the last summary attempt failed
+	SummaryAttempts          int64          `json:"summary_attempts"`                     // Attempts made by the current summary job
+	SummaryOptionsJSON       sql.NullString `json:"summary_options_json,omitempty"`       // Store the SummaryOptions of the meeting's summary as JSON
+	CurrentSummaryVersion    sql.NullInt64  `json:"summary_version,omitempty"`            // The SummaryVersion shown as the meeting's summary
+	ChaptersJSON             sql.NullString `json:"chapters_json,omitempty"`              // Store the meeting's Chapters as JSON array
+	CitationsJSON            sql.NullString `json:"citations_json,omitempty"`             // Store the SummaryCitations of the summary as JSON
+	TaskItemsJSON            sql.NullString `json:"task_items_json,omitempty"`            // Store the structured TaskItems of the summary as JSON array
+	UploadedAt               time.Time      `json:"uploaded_at"`
+	ModifiedAt               time.Time      `json:"modified_at"`
+	DeletedAt                sql.NullTime   `json:"-"` // Use '-' to exclude from default JSON responses
 }
 
+// Participants decodes ParticipantsJSON, returning nil if it is unset or invalid
+func (m *Meeting) Participants() []string {
+	return decodeStringList(m.ParticipantsJSON)
+}
+
+// Tasks decodes TasksJSON, returning nil if it is unset or invalid
+func (m *Meeting) Tasks() []string {
+	return decodeStringList(m.TasksJSON)
+}
+
+// RedactAllow decodes RedactAllowJSON, returning nil if it is unset or invalid
+func (m *Meeting) RedactAllow() []string {
+	return decodeStringList(m.RedactAllowJSON)
+}
+
+// RedactDeny decodes RedactDenyJSON, returning nil if it is unset or invalid
+func (m *Meeting) RedactDeny() []string {
+	return decodeStringList(m.RedactDenyJSON)
+}
+
+// Normalization decodes NormalizationJSON, returning empty settings if it is unset or invalid
+func (m *Meeting) Normalization() NormalizationSettings {
+	var settings NormalizationSettings
+	if m.NormalizationJSON.Valid && m.NormalizationJSON.String != "" {
+		json.Unmarshal([]byte(m.NormalizationJSON.String), &settings)
+	}
+	return settings
+}
+
+// Sections decodes SectionsJSON; every section is an empty list if it is unset or invalid
+func (m *Meeting) Sections() SummarySections {
+	var sections SummarySections
+	if m.SectionsJSON.Valid && m.SectionsJSON.String != "" {
+		json.Unmarshal([]byte(m.SectionsJSON.String), &sections)
+	}
+	return sections.NonNil()
+}
+
+// SummaryOptions decodes SummaryOptionsJSON, returning the default options if it is unset or invalid
+func (m *Meeting) SummaryOptions() SummaryOptions {
+	var options SummaryOptions
+	if m.SummaryOptionsJSON.Valid && m.SummaryOptionsJSON.String != "" {
+		json.Unmarshal([]byte(m.SummaryOptionsJSON.String), &options)
+	}
+	return options
+}
+
+// Citations decodes CitationsJSON, returning nil if it is unset or invalid
+func (m *Meeting) Citations() *SummaryCitations {
+	return decodeCitations(m.CitationsJSON)
+}
+
+func decodeCitations(s sql.NullString) *SummaryCitations {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	var citations SummaryCitations
+	if err := json.Unmarshal([]byte(s.String), &citations); err != nil {
+		return nil
+	}
+	return &citations
+}
+
+// TaskItems decodes TaskItemsJSON, returning nil if it is unset or invalid
+func (m *Meeting) TaskItems() []TaskItem {
+	return decodeTaskItems(m.TaskItemsJSON)
+}
+
+func decodeTaskItems(s sql.NullString) []TaskItem {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	var items []TaskItem
+	if err := json.Unmarshal([]byte(s.String), &items); err != nil {
+		return nil
+	}
+	return items
+}
+
+// Chapters decodes ChaptersJSON, returning an empty list if it is unset or invalid
+func (m *Meeting) Chapters() []Chapter {
+	chapters := []Chapter{}
+	if m.ChaptersJSON.Valid && m.ChaptersJSON.String != "" {
+		json.Unmarshal([]byte(m.ChaptersJSON.String), &chapters)
+	}
+	return chapters
+}
+
+// NormalizedUtterances decodes NormalizedUtterancesJSON, returning nil if it is unset or invalid
+func (m *Meeting) NormalizedUtterances() []Utterance {
+	if !m.NormalizedUtterancesJSON.Valid || m.NormalizedUtterancesJSON.String == "" {
+		return nil
+	}
+	var utterances []Utterance
+	if err := json.Unmarshal([]byte(m.NormalizedUtterancesJSON.String), &utterances); err != nil {
+		return nil
+	}
+	return utterances
+}
+
+func decodeStringList(s sql.NullString) []string {
+	if !s.Valid || s.String == "" {
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal([]byte(s.String), &list); err != nil {
+		return nil
+	}
+	return list
+}
+
+// ContentHash returns the hex encoded SHA-256 of uploaded transcript content
+func ContentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Summary job states
+const (
+	SummaryQueued    = "queued"
+	SummaryRunning   = "running"
+	SummarySucceeded = "succeeded"
+	SummaryFailed    = "failed"
+)
+
+// SummaryJobStatus reports the state of a meeting's summary generation
+type SummaryJobStatus struct {
+	Status   string `json:"status"` // Empty if no summary was ever scheduled, e.g. for a live meeting
+	Attempts int64  `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+}
+
+// SummaryJob returns the state of the meeting's summary generation
+func (m *Meeting) SummaryJob() SummaryJobStatus {
+	return SummaryJobStatus{
+		Status:   m.SummaryStatus.String,
+		Attempts: m.SummaryAttempts,
+		Error:    m.SummaryError.String,
+	}
+}
+
+// MeetingTime returns when the meeting took place, falling back to the upload time
+func (m *Meeting) MeetingTime() time.Time {
+	if m.ScheduledAt.Valid {
+		return m.ScheduledAt.Time
+	}
+	return m.UploadedAt
+}
+
+// ErrMeetingNotLive is returned when utterances are appended to a meeting that is not live
+var ErrMeetingNotLive = errors.New("meeting is not live")
 
 // MeetingRepository defines the interface for meeting data operations
 type MeetingRepository interface {
 	CreateMeeting(meeting *Meeting) (int64, error)
+	// CreateMeetingWithUtterances creates a meeting and its utterances atomically
+	CreateMeetingWithUtterances(meeting *Meeting, utterances []Utterance) (int64, error)
 	ListMeetings() ([]Meeting, error)
 	GetMeetingByID(id int64) (*Meeting, error)
+	GetMeetingByContentHash(hash string) (*Meeting, error)
 	UpdateMeeting(id int64, meeting *Meeting) error
+	SetNormalization(meetingID int64, settingsJSON, normalizedJSON sql.NullString) error
+	SetRedactionLists(meetingID int64, allowJSON, denyJSON sql.NullString) error
+	SetTranscript(meetingID int64, transcript string, contentHash sql.NullString) error
+	SetSummaryOptions(meetingID int64, optionsJSON sql.NullString) error
+	SetSummaryStatus(meetingID int64, status string, errMsg string) error
+	SaveUtterances(meetingID int64, utterances []Utterance) error
+	AppendUtterances(meetingID int64, utterances []Utterance, transcriptText string) error
+	// CloseLiveMeeting ends a live meeting, reporting false if it was not live
+	CloseLiveMeeting(meetingID int64) (bool, error)
+	ListUtterances(meetingID int64) ([]Utterance, error)
+	UpdateTranscript(meetingID int64, transcript string, utterances []Utterance) (int64, error)
+	ListTranscriptRevisions(meetingID int64) ([]TranscriptRevision, error)
+	// SaveSummary writes only the summary columns of a meeting and adds the summary as its
+	// current version, atomically, returning the version number
+	SaveSummary(meeting *Meeting, version *SummaryVersion) (int, error)
+	// PinSummary writes only the summary columns of a meeting and makes version the current one
+	PinSummary(meeting *Meeting, version int) error
+	ListSummaryVersions(meetingID int64) ([]SummaryVersion, error)
+	GetSummaryVersion(meetingID int64, version int) (*SummaryVersion, error)
 }
 
 // --- Existing structs (keeping them for now, might need adjustment later) ---
@@ -35,7 +232,8 @@ type MeetingRepository interface {
 // PostMeetingResponse represents the response for creating a meeting
 // Let's update this to return the ID as int64
 type PostMeetingResponse struct {
-	ID int64 `json:"id"`
+	ID        int64 `json:"id"`
+	Duplicate bool  `json:"duplicate,omitempty"` // Set when the upload matched an existing meeting's content
 }
 
 // GetMeetingsResponse represents the response for listing meetings
@@ -44,14 +242,103 @@ type GetMeetingsResponse struct {
 	Meetings []Meeting `json:"meetings"`
 }
 
+// CreateLiveMeetingRequest represents the request for starting a live meeting
+type CreateLiveMeetingRequest struct {
+	Title        string   `json:"title"`
+	Description  string   `json:"description"`
+	Participants []string `json:"participants"`
+	ScheduledAt  string   `json:"scheduled_at"` // RFC 3339 or YYYY-MM-DD [HH:MM]; defaults to now
+	Remark       string   `json:"remark"`
+}
+
+// AppendUtterancesResponse represents the response for appending utterances to a live meeting
+type AppendUtterancesResponse struct {
+	Appended int `json:"appended"`
+}
+
+// RedactionListsRequest represents the per-meeting redaction allow and deny lists
+type RedactionListsRequest struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
 // ChatMessage represents a chat message in the SSE stream
 type ChatMessage struct {
 	Data string `json:"data"`
 }
 
-
 // SummaryResponse represents the structured JSON response from the LLM
 type SummaryResponse struct {
 	Summary string   `json:"summary"`
 	Tasks   []string `json:"tasks"`
+	SummarySections
+	Citations *SummaryCitations `json:"citations,omitempty"`  // Set from the line markers in the reply, which are removed
+	TaskItems []TaskItem        `json:"task_items,omitempty"` // Structured Tasks, one per task; unset if they could not be extracted
+}
+
+// Task priorities
+const (
+	PriorityHigh   = "high"
+	PriorityMedium = "medium"
+	PriorityLow    = "low"
+)
+
+// TaskItem is a task of a summary with its details extracted
+type TaskItem struct {
+	Title    string `json:"title"`
+	Assignee string `json:"assignee,omitempty"` // Transcript speaker or meeting participant the task is assigned to
+	DueDate  string `json:"due_date,omitempty"` // YYYY-MM-DD, resolved against the meeting date
+	DueText  string `json:"due_text,omitempty"` // The deadline as said in the meeting, e.g. "by Friday"
+	Priority string `json:"priority,omitempty"` // One of the Priority* values; empty if the details are unknown
+}
+
+// SummaryOptions select how a meeting is summarized. They are kept with the meeting
+// and apply to every later regeneration, e.g. after a transcript edit.
+type SummaryOptions struct {
+	Template     string `json:"template,omitempty"`     // Named template from config.yml; the default system message if empty
+	Instructions string `json:"instructions,omitempty"` // Additional instructions for the summary model
+}
+
+// GetSummaryTemplatesResponse represents the response for listing the summary templates
+type GetSummaryTemplatesResponse struct {
+	Templates []string `json:"templates"`
+}
+
+// SummarySections are the minutes sections beyond the summary and tasks.
+// Meetings summarized before they were added have none.
+type SummarySections struct {
+	Decisions     []string `json:"decisions"`
+	OpenQuestions []string `json:"open_questions"`
+	Risks         []string `json:"risks"` // Risks and blockers
+	Topics        []string `json:"topics"`
+}
+
+// SourceRange is a stretch of consecutive utterances that a summary item is based on
+type SourceRange struct {
+	StartSeq int   `json:"start_seq"` // Seq of the first utterance, as returned by GET /utterances
+	EndSeq   int   `json:"end_seq"`
+	StartMs  int64 `json:"start_ms"`
+	EndMs    int64 `json:"end_ms"`
+}
+
+// SummaryCitations are the transcript ranges each part of a summary is based on. Every
+// list is parallel to the one it cites: Summary has an entry per line of the summary
+// text, Tasks per task and so on. Items without citations have an empty list.
+type SummaryCitations struct {
+	Summary       [][]SourceRange `json:"summary"`
+	Tasks         [][]SourceRange `json:"tasks"`
+	Decisions     [][]SourceRange `json:"decisions"`
+	OpenQuestions [][]SourceRange `json:"open_questions"`
+	Risks         [][]SourceRange `json:"risks"`
+	Topics        [][]SourceRange `json:"topics"`
+}
+
+// NonNil replaces missing sections with empty lists so they encode as [] rather than null
+func (s SummarySections) NonNil() SummarySections {
+	for _, list := range []*[]string{&s.Decisions, &s.OpenQuestions, &s.Risks, &s.Topics} {
+		if *list == nil {
+			*list = []string{}
+		}
+	}
+	return s
 }
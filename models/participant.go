@@ -0,0 +1,75 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Participant is a person whose speaker names are linked across meetings
+type Participant struct {
+	ID          int64          `json:"id"`
+	DisplayName string         `json:"display_name"` // Unique
+	Email       sql.NullString `json:"email,omitempty"`
+	AliasesJSON sql.NullString `json:"aliases_json,omitempty"` // Store other spellings of the name as JSON string array
+	CreatedAt   time.Time      `json:"created_at"`
+	ModifiedAt  time.Time      `json:"modified_at"`
+}
+
+// Aliases decodes AliasesJSON, returning nil if it is unset or invalid
+func (p *Participant) Aliases() []string {
+	return decodeStringList(p.AliasesJSON)
+}
+
+// MeetingSpeaker links a speaker name in a meeting's transcript to a participant.
+// Links are matched by name and alias unless they were set manually; a manual
+// link without a participant marks the speaker as not registered.
+type MeetingSpeaker struct {
+	MeetingID     int64         `json:"meeting_id"`
+	Speaker       string        `json:"speaker"`
+	ParticipantID sql.NullInt64 `json:"participant_id"`
+	Manual        bool          `json:"manual"`
+}
+
+// ParticipantRepository defines the interface for participant data operations
+type ParticipantRepository interface {
+	CreateParticipant(participant *Participant) (int64, error)
+	ListParticipants() ([]Participant, error)
+	GetParticipantByID(id int64) (*Participant, error)
+	UpdateParticipant(id int64, participant *Participant) error
+	ListMeetingSpeakers(meetingID int64) ([]MeetingSpeaker, error)
+	SetMeetingSpeaker(link MeetingSpeaker) error
+	ReplaceMatchedSpeakers(meetingID int64, links []MeetingSpeaker) error
+	ListMeetingsByParticipant(participantID int64) ([]Meeting, error)
+	// ListSpeakerMeetingIDs returns the meetings in which one of the names spoke, ignoring
+	// case, or that have a speaker linked to the participant
+	ListSpeakerMeetingIDs(participantID int64, names []string) ([]int64, error)
+}
+
+// ParticipantRequest represents the request for creating or updating a participant
+type ParticipantRequest struct {
+	DisplayName string   `json:"display_name"`
+	Email       string   `json:"email"`
+	Aliases     []string `json:"aliases"`
+}
+
+// PostParticipantResponse represents the response for creating a participant
+type PostParticipantResponse struct {
+	ID int64 `json:"id"`
+}
+
+// GetParticipantsResponse represents the response for listing participants
+type GetParticipantsResponse struct {
+	Participants []Participant `json:"participants"`
+}
+
+// GetMeetingSpeakersResponse represents the response for listing a meeting's speakers
+// along with the participants they are linked to
+type GetMeetingSpeakersResponse struct {
+	Speakers []MeetingSpeaker `json:"speakers"`
+}
+
+// SetMeetingSpeakersRequest represents manual speaker links; a null participant ID
+// marks the speaker as not being a registered participant
+type SetMeetingSpeakersRequest struct {
+	Links map[string]*int64 `json:"links"`
+}
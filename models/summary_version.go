@@ -0,0 +1,107 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// TokenUsage counts the model tokens spent on generating a summary, over all of its requests
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Add adds the tokens of further requests
+func (u *TokenUsage) Add(other TokenUsage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+}
+
+// SummaryVersion is one generated summary of a meeting. Every regeneration adds a
+// version; the meeting shows the current one.
+type SummaryVersion struct {
+	ID            int64          `json:"-"`
+	MeetingID     int64          `json:"meeting_id"`
+	Version       int            `json:"version"` // 1-based number within the meeting
+	SummaryText   string         `json:"summary"`
+	TasksJSON     sql.NullString `json:"-"` // Store tasks as JSON string array
+	SectionsJSON  sql.NullString `json:"-"` // Store SummarySections as JSON
+	CitationsJSON sql.NullString `json:"-"` // Store SummaryCitations as JSON
+	TaskItemsJSON sql.NullString `json:"-"` // Store TaskItems as JSON
+	ChaptersJSON  sql.NullString `json:"-"` // Store the Chapters generated with the summary as JSON; unset for versions older than chapter versioning
+	Model         sql.NullString `json:"-"` // Summary model; unknown for summaries generated before versions were kept
+	Template      sql.NullString `json:"-"`
+	Instructions  sql.NullString `json:"-"`
+	Usage         TokenUsage     `json:"usage"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+// Tasks decodes TasksJSON, returning nil if it is unset or invalid
+func (v *SummaryVersion) Tasks() []string {
+	return decodeStringList(v.TasksJSON)
+}
+
+// Sections decodes SectionsJSON; every section is an empty list if it is unset or invalid
+func (v *SummaryVersion) Sections() SummarySections {
+	var sections SummarySections
+	if v.SectionsJSON.Valid && v.SectionsJSON.String != "" {
+		json.Unmarshal([]byte(v.SectionsJSON.String), &sections)
+	}
+	return sections.NonNil()
+}
+
+// Citations decodes CitationsJSON, returning nil if it is unset or invalid
+func (v *SummaryVersion) Citations() *SummaryCitations {
+	return decodeCitations(v.CitationsJSON)
+}
+
+// TaskItems decodes TaskItemsJSON, returning nil if it is unset or invalid
+func (v *SummaryVersion) TaskItems() []TaskItem {
+	return decodeTaskItems(v.TaskItemsJSON)
+}
+
+// SummaryVersionResponse represents a summary version in API responses
+type SummaryVersionResponse struct {
+	Version      int        `json:"version"`
+	Current      bool       `json:"current"`
+	Model        string     `json:"model,omitempty"`
+	Template     string     `json:"template,omitempty"`
+	Instructions string     `json:"instructions,omitempty"`
+	Usage        TokenUsage `json:"usage"`
+	CreatedAt    time.Time  `json:"created_at"`
+	SummaryResponse
+}
+
+// GetSummaryVersionsResponse represents the response for listing a meeting's summary versions, newest first
+type GetSummaryVersionsResponse struct {
+	Versions []SummaryVersionResponse `json:"versions"`
+}
+
+// ListDiff is the difference between two versions of a list of summary items.
+// Items that were reworded rather than replaced are reported as changed.
+type ListDiff struct {
+	Added   []string     `json:"added"`
+	Removed []string     `json:"removed"`
+	Changed []ItemChange `json:"changed"`
+}
+
+// ItemChange is an item reworded between two versions
+type ItemChange struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// SummaryDiff is the structured difference between two summary versions of a meeting
+type SummaryDiff struct {
+	From           int      `json:"from"`
+	To             int      `json:"to"`
+	SummaryChanged bool     `json:"summary_changed"`
+	Tasks          ListDiff `json:"tasks"`
+	Decisions      ListDiff `json:"decisions"`
+	OpenQuestions  ListDiff `json:"open_questions"`
+	Risks          ListDiff `json:"risks"`
+	Topics         ListDiff `json:"topics"`
+}
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Utterance represents a single speaker turn parsed from a meeting transcript
+type Utterance struct {
+	ID        int64  `json:"id"`
+	MeetingID int64  `json:"meeting_id"`
+	Seq       int    `json:"seq"` // 0-based position within the meeting
+	Speaker   string `json:"speaker"`
+	StartMs   int64  `json:"start_ms"` // Offset from the start of the meeting in milliseconds
+	EndMs     int64  `json:"end_ms"`
+	Text      string `json:"text"`
+
+	SourceSeqs []int `json:"source_seqs,omitempty"` // Set on normalized utterances: the Seqs of the original utterances merged into this one
+}
+
+// GetUtterancesResponse represents the response for listing a meeting's utterances
+type GetUtterancesResponse struct {
+	Utterances []Utterance `json:"utterances"`
+}
+
+// LiveSegment is a transcript segment pushed by a client over the live WebSocket.
+// Partial segments are acknowledged but only final ones are stored.
+type LiveSegment struct {
+	SegmentID string `json:"segment_id"`
+	Final     bool   `json:"final"`
+	Speaker   string `json:"speaker"`
+	StartMs   int64  `json:"start_ms"`
+	EndMs     int64  `json:"end_ms"`
+	Text      string `json:"text"`
+}
+
+// LiveServerMessage is sent from the server over the live WebSocket.
+// Type is "ack", "error" or "summary".
+type LiveServerMessage struct {
+	Type      string           `json:"type"`
+	SegmentID string           `json:"segment_id,omitempty"`
+	Final     bool             `json:"final,omitempty"`
+	Error     string           `json:"error,omitempty"`
+	Summary   *SummaryResponse `json:"summary,omitempty"`
+}
+
+// TranscriptRevision is a snapshot of a meeting's transcript and summary taken before an edit
+type TranscriptRevision struct {
+	ID             int64          `json:"id"`
+	MeetingID      int64          `json:"meeting_id"`
+	Transcript     sql.NullString `json:"transcript,omitempty"`
+	UtterancesJSON sql.NullString `json:"utterances_json,omitempty"` // Store utterances as JSON array
+	SummaryText    sql.NullString `json:"summary_text,omitempty"`
+	TasksJSON      sql.NullString `json:"tasks_json,omitempty"`
+	TasksStatusNum int64          `json:"tasks_status_num"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// UpdateTranscriptRequest represents a transcript correction. Either the whole
+// transcript is replaced, or individual utterances are edited; speakers can be
+// renamed in both cases.
+type UpdateTranscriptRequest struct {
+	Transcript *string           `json:"transcript,omitempty"` // Replacement in any format accepted by POST /meeting
+	Utterances []UtteranceEdit   `json:"utterances,omitempty"`
+	Speakers   map[string]string `json:"speakers,omitempty"` // Old speaker name to new speaker name
+}
+
+// UtteranceEdit corrects the speaker and/or text of the utterance with the given seq
+type UtteranceEdit struct {
+	Seq     int     `json:"seq"`
+	Speaker *string `json:"speaker,omitempty"`
+	Text    *string `json:"text,omitempty"`
+}
+
+// UpdateTranscriptResponse represents the response for a transcript correction
+type UpdateTranscriptResponse struct {
+	ID         int64 `json:"id"`
+	RevisionID int64 `json:"revision_id"` // Snapshot of the transcript before the edit
+}
+
+// GetTranscriptRevisionsResponse represents the response for listing a meeting's transcript revisions
+type GetTranscriptRevisionsResponse struct {
+	Revisions []TranscriptRevision `json:"revisions"`
+}
+
+// NormalizationSettings are a meeting's overrides of the configured transcript
+// normalization. Unset fields use the configured value; aliases are added to the
+// configured ones.
+type NormalizationSettings struct {
+	Enabled       *bool               `json:"enabled,omitempty"`
+	MergeAdjacent *bool               `json:"merge_adjacent,omitempty"`
+	MaxGapSeconds *int                `json:"max_gap_seconds,omitempty"`
+	StripFillers  *bool               `json:"strip_fillers,omitempty"`
+	Aliases       map[string][]string `json:"aliases,omitempty"` // Canonical speaker name to alternative spellings
+}
+
+// NormalizationResponse represents a meeting's normalization settings and the resulting utterances
+type NormalizationResponse struct {
+	Settings   NormalizationSettings `json:"settings"`
+	Utterances []Utterance           `json:"utterances"`
+}
+
+// Chapter is a part of a meeting about one topic, located by the timestamps of its utterances
+type Chapter struct {
+	Title    string `json:"title"`
+	StartMs  int64  `json:"start_ms"`
+	EndMs    int64  `json:"end_ms"`
+	StartSeq int    `json:"start_seq"` // Seq of the first utterance, as returned by GET /utterances
+	EndSeq   int    `json:"end_seq"`   // Seq of the last utterance
+	Summary  string `json:"summary"`
+}
+
+// GetChaptersResponse represents the response for listing a meeting's chapters along
+// with the state of the summary job that generates them
+type GetChaptersResponse struct {
+	Chapters []Chapter `json:"chapters"`
+	SummaryJobStatus
+}
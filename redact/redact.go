@@ -0,0 +1,196 @@
+// Package redact replaces personal information in text with stable
+// placeholders before it is sent to a model, and maps them back afterwards.
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Rule is a named pattern; matches are replaced with "[NAME_n]" placeholders
+type Rule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultRules are used when redaction is enabled without configured rules
+func DefaultRules() []Rule {
+	return []Rule{
+		{Name: "EMAIL", Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)},
+		{Name: "ID_NUMBER", Pattern: regexp.MustCompile(`\b[1-9]\d{5}(?:19|20)\d{2}(?:0[1-9]|1[0-2])(?:0[1-9]|[12]\d|3[01])\d{3}[\dXx]\b`)},
+		{Name: "PHONE", Pattern: regexp.MustCompile(`(?:\+?86[- ]?)?\b1[3-9]\d[- ]?\d{4}[- ]?\d{4}\b`)},
+		{Name: "PHONE", Pattern: regexp.MustCompile(`\b0\d{2,3}-\d{7,8}\b`)},
+	}
+}
+
+// denyRuleName is the placeholder name used for deny list terms
+const denyRuleName = "REDACTED"
+
+// placeholderPattern matches the placeholders produced by a Redactor
+var placeholderPattern = regexp.MustCompile(`\[[A-Z][A-Z0-9_]*_\d+\]`)
+
+// Redactor replaces matches of its rules and deny list with placeholders.
+// The same value always maps to the same placeholder for the lifetime of the
+// Redactor, so use one per meeting. A nil *Redactor leaves text unchanged.
+type Redactor struct {
+	rules []Rule
+	allow map[string]bool
+	deny  []string
+
+	mu           sync.Mutex
+	placeholders map[string]string // value -> placeholder
+	values       map[string]string // placeholder -> value
+	counts       map[string]int    // rule name -> placeholders issued
+}
+
+// New creates a Redactor. Values in allow are never redacted; terms in deny always are.
+func New(rules []Rule, allow, deny []string) *Redactor {
+	r := &Redactor{
+		rules:        rules,
+		allow:        make(map[string]bool),
+		placeholders: make(map[string]string),
+		values:       make(map[string]string),
+		counts:       make(map[string]int),
+	}
+	for _, a := range allow {
+		if a = strings.TrimSpace(a); a != "" {
+			r.allow[a] = true
+		}
+	}
+	for _, d := range deny {
+		if d = strings.TrimSpace(d); d != "" && !r.allow[d] {
+			r.deny = append(r.deny, d)
+		}
+	}
+	// Prefer longer terms where deny terms overlap
+	sort.SliceStable(r.deny, func(i, j int) bool { return len(r.deny[i]) > len(r.deny[j]) })
+	return r
+}
+
+type span struct {
+	start, end int
+	rule       string
+}
+
+// Redact returns text with every match replaced by its placeholder
+func (r *Redactor) Redact(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+
+	var spans []span
+	for _, term := range r.deny {
+		for offset := 0; ; {
+			i := strings.Index(text[offset:], term)
+			if i < 0 {
+				break
+			}
+			spans = append(spans, span{offset + i, offset + i + len(term), denyRuleName})
+			offset += i + len(term)
+		}
+	}
+	for _, rule := range r.rules {
+		for _, loc := range rule.Pattern.FindAllStringIndex(text, -1) {
+			if loc[0] == loc[1] || r.allow[text[loc[0]:loc[1]]] {
+				continue
+			}
+			spans = append(spans, span{loc[0], loc[1], rule.Name})
+		}
+	}
+	if len(spans) == 0 {
+		return text
+	}
+
+	// Earliest match wins, then the longest; overlapping later matches are dropped
+	sort.SliceStable(spans, func(i, j int) bool {
+		if spans[i].start != spans[j].start {
+			return spans[i].start < spans[j].start
+		}
+		return spans[i].end > spans[j].end
+	})
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var sb strings.Builder
+	last := 0
+	for _, s := range spans {
+		if s.start < last {
+			continue
+		}
+		sb.WriteString(text[last:s.start])
+		sb.WriteString(r.placeholderFor(text[s.start:s.end], s.rule))
+		last = s.end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// placeholderFor returns the stable placeholder of value; r.mu must be held
+func (r *Redactor) placeholderFor(value, rule string) string {
+	if p, ok := r.placeholders[value]; ok {
+		return p
+	}
+	r.counts[rule]++
+	p := fmt.Sprintf("[%s_%d]", rule, r.counts[rule])
+	r.placeholders[value] = p
+	r.values[p] = value
+	return p
+}
+
+// Restore replaces the placeholders this Redactor issued with their original values
+func (r *Redactor) Restore(text string) string {
+	if r == nil || text == "" {
+		return text
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return placeholderPattern.ReplaceAllStringFunc(text, func(p string) string {
+		if v, ok := r.values[p]; ok {
+			return v
+		}
+		return p
+	})
+}
+
+// StreamRestorer restores placeholders in streamed text, holding back a
+// trailing fragment that may be the start of a placeholder split across chunks
+type StreamRestorer struct {
+	r       *Redactor
+	pending string
+}
+
+// maxPlaceholderLen bounds how much text is held back waiting for a placeholder to complete
+const maxPlaceholderLen = 48
+
+var placeholderPrefix = regexp.MustCompile(`^\[[A-Z0-9_]*$`)
+
+// NewStreamRestorer creates a StreamRestorer for placeholders issued by r.
+// With a nil r the text passes through unchanged.
+func NewStreamRestorer(r *Redactor) *StreamRestorer {
+	return &StreamRestorer{r: r}
+}
+
+// Write adds a chunk and returns the text that can be emitted so far
+func (s *StreamRestorer) Write(chunk string) string {
+	text := s.pending + chunk
+	s.pending = ""
+	if s.r == nil {
+		return text
+	}
+	if i := strings.LastIndexByte(text, '['); i >= 0 && len(text)-i <= maxPlaceholderLen && placeholderPrefix.MatchString(text[i:]) {
+		s.pending = text[i:]
+		text = text[:i]
+	}
+	return s.r.Restore(text)
+}
+
+// Flush returns any text still held back
+func (s *StreamRestorer) Flush() string {
+	text := s.pending
+	s.pending = ""
+	return s.r.Restore(text)
+}
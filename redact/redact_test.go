@@ -0,0 +1,116 @@
+package redact
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactor(t *testing.T) {
+	tests := []struct {
+		name  string
+		allow []string
+		deny  []string
+		text  string
+		want  string
+	}{
+		{
+			name: "default rules",
+			text: "邮箱 lily@example.com，电话 138-1234-5678，座机 010-12345678",
+			want: "邮箱 [EMAIL_1]，电话 [PHONE_1]，座机 [PHONE_2]",
+		},
+		{
+			name: "id number",
+			text: "身份证 11010519491231002X 已登记",
+			want: "身份证 [ID_NUMBER_1] 已登记",
+		},
+		{
+			name: "same value same placeholder",
+			text: "a@b.cn 和 c@d.cn 以及 a@b.cn",
+			want: "[EMAIL_1] 和 [EMAIL_2] 以及 [EMAIL_1]",
+		},
+		{
+			name:  "allow list",
+			allow: []string{"support@example.com"},
+			text:  "联系 support@example.com 或 lily@example.com",
+			want:  "联系 support@example.com 或 [EMAIL_1]",
+		},
+		{
+			name: "deny list prefers longer terms",
+			deny: []string{"Lily", "Lily Chen", " "},
+			text: "Lily Chen 说 Lily 会跟进",
+			want: "[REDACTED_1] 说 [REDACTED_2] 会跟进",
+		},
+		{
+			name:  "allowed deny term is kept",
+			allow: []string{"Andy"},
+			deny:  []string{"Andy"},
+			text:  "Andy 负责",
+			want:  "Andy 负责",
+		},
+		{
+			name: "nothing to redact",
+			text: "下周五前完成原型",
+			want: "下周五前完成原型",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := New(DefaultRules(), tt.allow, tt.deny)
+			got := r.Redact(tt.text)
+			if got != tt.want {
+				t.Fatalf("Redact() = %q, want %q", got, tt.want)
+			}
+			if restored := r.Restore(got); restored != tt.text {
+				t.Errorf("Restore() = %q, want %q", restored, tt.text)
+			}
+		})
+	}
+}
+
+func TestRedactorRestoreUnknownPlaceholders(t *testing.T) {
+	r := New(DefaultRules(), nil, nil)
+	r.Redact("lily@example.com")
+	if got := r.Restore("[EMAIL_1] [EMAIL_2] [1]"); got != "lily@example.com [EMAIL_2] [1]" {
+		t.Errorf("Restore() = %q", got)
+	}
+
+	var nilRedactor *Redactor
+	if got := nilRedactor.Redact("lily@example.com"); got != "lily@example.com" {
+		t.Errorf("nil Redact() = %q", got)
+	}
+}
+
+func TestStreamRestorer(t *testing.T) {
+	r := New(DefaultRules(), nil, nil)
+	r.Redact("lily@example.com 138-1234-5678")
+
+	tests := []struct {
+		name   string
+		chunks []string
+		want   string
+	}{
+		{
+			name:   "placeholder split across chunks",
+			chunks: []string{"请联系 [EMA", "IL_1] 或 [PHONE", "_1]。"},
+			want:   "请联系 lily@example.com 或 138-1234-5678。",
+		},
+		{
+			name:   "bracket that is no placeholder",
+			chunks: []string{"见 [", "附件] 和 [EMAIL_1"},
+			want:   "见 [附件] 和 [EMAIL_1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewStreamRestorer(r)
+			var sb strings.Builder
+			for _, chunk := range tt.chunks {
+				sb.WriteString(s.Write(chunk))
+			}
+			sb.WriteString(s.Flush())
+			if got := sb.String(); got != tt.want {
+				t.Errorf("streamed %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"meetingagent/config"
+	"meetingagent/models"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// chapterJSONFormat is the reply format requested when splitting a meeting into chapters
+const chapterJSONFormat = `{"chapters": [{"title": "...", "start": 0, "end": 0, "summary": "..."}]}`
+
+// chapterMessage is the system message for splitting a numbered transcript into chapters
+const chapterMessage = `你将收到一段会议记录，每行的格式为 "[L行号] 开始时间-结束时间 发言人: 内容"，例如 [L12] 表示第 12 行。
+请按讨论的主题将这段记录按时间顺序划分为若干章节，每个章节由连续的若干行组成，章节之间不重叠。
+只输出一个 JSON 对象，格式为 ` + chapterJSONFormat + `。
+start 和 end 为章节第一行和最后一行的行号，只填数字，不带 L；title 为简短的章节标题；summary 用一两句话概括该章节。`
+
+// chapterSpan is a chapter as returned by the model, located by line numbers
+type chapterSpan struct {
+	Title   string `json:"title"`
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	Summary string `json:"summary"`
+}
+
+// GetMeetingChapters splits a meeting into chapters by topic, using the timestamps of
+// its (normalized) utterances. Meetings without timestamps or shorter than the configured
+// minimum have no chapters. It also returns the tokens spent on all model requests.
+func GetMeetingChapters(ctx context.Context, meeting *models.Meeting, utterances []models.Utterance) ([]models.Chapter, models.TokenUsage, error) {
+	var usage models.TokenUsage
+	utterances = summaryUtterances(meeting, utterances)
+	minMinutes := config.AppConfig.Summary.ChapterMinMinutes
+	if minMinutes < 0 || len(utterances) == 0 {
+		return nil, usage, nil
+	}
+	duration := time.Duration(utterances[len(utterances)-1].EndMs-utterances[0].StartMs) * time.Millisecond
+	if duration <= 0 || duration < time.Duration(minMinutes)*time.Minute {
+		return nil, usage, nil
+	}
+	if SummaryChatModel == nil {
+		return nil, usage, fmt.Errorf("summary chat model not initialized")
+	}
+
+	redactor := NewMeetingRedactor(meeting)
+	meetingInfo := redactor.Redact(FormatMeetingInfo(meeting))
+	lines := numberedLines(utterances)
+
+	// Long meetings are split in parts of consecutive lines, and the chapters of all parts joined
+	var spans []chapterSpan
+	for _, part := range chapterParts(lines, config.AppConfig.SummaryTokenBudget()) {
+		messages := []*schema.Message{
+			{
+				Role:    schema.System,
+				Content: chapterMessage,
+			},
+			{
+				Role:    schema.User,
+				Content: meetingInfo,
+			},
+			{
+				Role:    schema.User,
+				Content: redactor.Redact(strings.Join(lines[part[0]:part[1]+1], "\n")),
+			},
+		}
+		var partSpans []chapterSpan
+		err := generateJSON(ctx, &usage, messages, chapterJSONFormat, func(content string) (err error) {
+			partSpans, err = parseChapterSpans(content, part[0], part[1])
+			return err
+		})
+		if err != nil {
+			return nil, usage, fmt.Errorf("failed to generate chapters: %w", err)
+		}
+		spans = append(spans, partSpans...)
+	}
+
+	chapters := make([]models.Chapter, 0, len(spans))
+	for _, span := range orderChapterSpans(spans) {
+		r := sourceRange(utterances, span.Start, span.End)
+		chapter := models.Chapter{
+			Title:    span.Title,
+			StartMs:  r.StartMs,
+			EndMs:    r.EndMs,
+			StartSeq: r.StartSeq,
+			EndSeq:   r.EndSeq,
+			Summary:  span.Summary,
+		}
+		if RestoreOutput() {
+			chapter.Title = redactor.Restore(chapter.Title)
+			chapter.Summary = redactor.Restore(chapter.Summary)
+		}
+		chapters = append(chapters, chapter)
+	}
+	return chapters, usage, nil
+}
+
+// chapterParts groups consecutive lines into parts of at most budget estimated tokens
+// and returns the first and last line of each. A line over the budget is a part of its own.
+func chapterParts(lines []string, budget int) [][2]int {
+	var parts [][2]int
+	start, tokens := 0, 0
+	for i, line := range lines {
+		lineTokens := EstimateTokens(line) + 1
+		if i > start && tokens+lineTokens > budget {
+			parts = append(parts, [2]int{start, i - 1})
+			start, tokens = i, 0
+		}
+		tokens += lineTokens
+	}
+	return append(parts, [2]int{start, len(lines) - 1})
+}
+
+// parseChapterSpans extracts and validates the chapters in a model reply for lines first to last.
+// The error describes what is wrong so that it can be sent back to the model.
+func parseChapterSpans(content string, first, last int) ([]chapterSpan, error) {
+	object, err := extractJSONObject(content)
+	if err != nil {
+		return nil, err
+	}
+	var reply struct {
+		Chapters []chapterSpan `json:"chapters"`
+	}
+	if err := json.Unmarshal([]byte(removeTrailingCommas(object)), &reply); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if len(reply.Chapters) == 0 {
+		return nil, errors.New(`field "chapters" must be a non-empty array`)
+	}
+	for i, span := range reply.Chapters {
+		if strings.TrimSpace(span.Title) == "" {
+			return nil, fmt.Errorf("chapter %d has no title", i+1)
+		}
+		if span.Start < first || span.End > last || span.Start > span.End {
+			return nil, fmt.Errorf("chapter %d must cover lines between %d and %d with start <= end", i+1, first, last)
+		}
+	}
+	return reply.Chapters, nil
+}
+
+// orderChapterSpans sorts chapters by their first line and trims any overlap with the previous chapter
+func orderChapterSpans(spans []chapterSpan) []chapterSpan {
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start < spans[j].Start })
+	ordered := spans[:0]
+	for _, span := range spans {
+		if n := len(ordered); n > 0 && span.Start <= ordered[n-1].End {
+			span.Start = ordered[n-1].End + 1
+		}
+		if span.Start > span.End {
+			continue
+		}
+		ordered = append(ordered, span)
+	}
+	return ordered
+}
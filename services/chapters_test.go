@@ -0,0 +1,45 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrderChapterSpans(t *testing.T) {
+	tests := []struct {
+		name  string
+		spans []chapterSpan
+		want  []chapterSpan
+	}{
+		{
+			name: "empty",
+			want: []chapterSpan{},
+		},
+		{
+			name:  "sorted by first line",
+			spans: []chapterSpan{{Title: "b", Start: 5, End: 9}, {Title: "a", Start: 0, End: 4}},
+			want:  []chapterSpan{{Title: "a", Start: 0, End: 4}, {Title: "b", Start: 5, End: 9}},
+		},
+		{
+			name:  "overlap is trimmed",
+			spans: []chapterSpan{{Title: "a", Start: 0, End: 6}, {Title: "b", Start: 4, End: 9}},
+			want:  []chapterSpan{{Title: "a", Start: 0, End: 6}, {Title: "b", Start: 7, End: 9}},
+		},
+		{
+			name:  "chapter inside the previous one is dropped",
+			spans: []chapterSpan{{Title: "a", Start: 0, End: 9}, {Title: "b", Start: 2, End: 5}, {Title: "c", Start: 10, End: 12}},
+			want:  []chapterSpan{{Title: "a", Start: 0, End: 9}, {Title: "c", Start: 10, End: 12}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := orderChapterSpans(tt.spans)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("orderChapterSpans() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
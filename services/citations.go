@@ -0,0 +1,137 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"meetingagent/models"
+	"meetingagent/transcript"
+)
+
+// summaryCitationMessage asks the summary model to mark the transcript lines each item is based on
+const summaryCitationMessage = `会议记录的每行以 [L行号] 开头，例如 [L12]。请在 summary 的每一行末尾，以及 tasks、decisions、open_questions、risks、topics 的每一项末尾，
+用同样的格式标注其依据的记录行，例如 "完成原型开发 [L12][L15-L18]"，不要用其他格式的方括号标注行号。合并多份总结时请保留这些标注。`
+
+// citationMarker matches a line marker such as [L12], [L3,L5] or [L15-L18]. The L keeps
+// bracketed numbers that belong to the text, such as "[1]" or "[2024]", from being taken
+// for citations.
+var citationMarker = regexp.MustCompile(`\s*\[(L\d+(?:\s*[-–,，]\s*L?\d+)*)\]`)
+
+// summaryUtterances returns the utterances given to the summary model: the normalized
+// ones if the meeting has any, else the stored ones
+func summaryUtterances(meeting *models.Meeting, utterances []models.Utterance) []models.Utterance {
+	if normalized := meeting.NormalizedUtterances(); len(normalized) > 0 {
+		return normalized
+	}
+	return utterances
+}
+
+// numberedLines renders one line per utterance, prefixed with its index as "[Ln] "
+func numberedLines(utterances []models.Utterance) []string {
+	lines := make([]string, len(utterances))
+	for i, u := range utterances {
+		lines[i] = fmt.Sprintf("[L%d] %s", i, strings.TrimRight(transcript.Render([]models.Utterance{u}), "\n"))
+	}
+	return lines
+}
+
+// sourceRange locates the lines first to last of numberedLines in the stored transcript.
+// Normalized utterances refer to the stored ones they were merged from.
+func sourceRange(utterances []models.Utterance, first, last int) models.SourceRange {
+	start, end := utterances[first], utterances[last]
+	r := models.SourceRange{StartSeq: start.Seq, EndSeq: end.Seq, StartMs: start.StartMs, EndMs: end.EndMs}
+	if len(start.SourceSeqs) > 0 {
+		r.StartSeq = start.SourceSeqs[0]
+	}
+	if len(end.SourceSeqs) > 0 {
+		r.EndSeq = end.SourceSeqs[len(end.SourceSeqs)-1]
+	}
+	return r
+}
+
+// extractCitations removes the line markers from the summary lines and items and
+// returns the transcript ranges they cite, parallel to them. Markers for lines that
+// don't exist are dropped.
+func extractCitations(sr *models.SummaryResponse, utterances []models.Utterance) *models.SummaryCitations {
+	cite := func(item string) (string, []models.SourceRange) {
+		var lines []int
+		for _, m := range citationMarker.FindAllStringSubmatch(item, -1) {
+			lines = append(lines, parseLineNumbers(m[1], len(utterances))...)
+		}
+		return strings.TrimSpace(citationMarker.ReplaceAllString(item, "")), citedRanges(utterances, lines)
+	}
+	citeList := func(items []string) [][]models.SourceRange {
+		ranges := make([][]models.SourceRange, len(items))
+		for i, item := range items {
+			items[i], ranges[i] = cite(item)
+		}
+		return ranges
+	}
+
+	summaryLines := strings.Split(sr.Summary, "\n")
+	citations := &models.SummaryCitations{
+		Summary:       make([][]models.SourceRange, len(summaryLines)),
+		Tasks:         citeList(sr.Tasks),
+		Decisions:     citeList(sr.Decisions),
+		OpenQuestions: citeList(sr.OpenQuestions),
+		Risks:         citeList(sr.Risks),
+		Topics:        citeList(sr.Topics),
+	}
+	for i, line := range summaryLines {
+		// Keep the indentation of bullets; only the markers are removed
+		text, ranges := cite(line)
+		if text != "" {
+			text = line[:len(line)-len(strings.TrimLeft(line, " \t"))] + text
+		}
+		summaryLines[i], citations.Summary[i] = text, ranges
+	}
+	sr.Summary = strings.Join(summaryLines, "\n")
+	return citations
+}
+
+// parseLineNumbers parses the contents of a citation marker, keeping line numbers below n
+func parseLineNumbers(s string, n int) []int {
+	var lines []int
+	for _, part := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '，' }) {
+		bounds := strings.FieldsFunc(part, func(r rune) bool { return r == '-' || r == '–' })
+		if len(bounds) == 0 || len(bounds) > 2 {
+			continue
+		}
+		first, err := strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(bounds[0]), "L"))
+		if err != nil {
+			continue
+		}
+		last := first
+		if len(bounds) == 2 {
+			if last, err = strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(bounds[1]), "L")); err != nil || last < first {
+				continue
+			}
+		}
+		for line := first; line <= last && line < n; line++ {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// citedRanges groups cited line numbers into ranges of consecutive lines
+func citedRanges(utterances []models.Utterance, lines []int) []models.SourceRange {
+	ranges := []models.SourceRange{}
+	if len(lines) == 0 {
+		return ranges
+	}
+	sort.Ints(lines)
+	first, last := lines[0], lines[0]
+	for _, line := range lines[1:] {
+		if line <= last+1 {
+			last = max(last, line)
+			continue
+		}
+		ranges = append(ranges, sourceRange(utterances, first, last))
+		first, last = line, line
+	}
+	return append(ranges, sourceRange(utterances, first, last))
+}
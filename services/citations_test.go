@@ -0,0 +1,84 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"meetingagent/models"
+)
+
+func TestExtractCitations(t *testing.T) {
+	utterances := []models.Utterance{
+		{Seq: 0, StartMs: 0, EndMs: 1000},
+		{Seq: 1, StartMs: 1000, EndMs: 2000},
+		{Seq: 2, StartMs: 2000, EndMs: 3000},
+		{Seq: 3, StartMs: 3000, EndMs: 4000},
+		// A normalized utterance merged from stored ones
+		{Seq: 4, StartMs: 4000, EndMs: 6000, SourceSeqs: []int{4, 5}},
+	}
+	r := func(startSeq, endSeq int, startMs, endMs int64) models.SourceRange {
+		return models.SourceRange{StartSeq: startSeq, EndSeq: endSeq, StartMs: startMs, EndMs: endMs}
+	}
+	tests := []struct {
+		name      string
+		item      string
+		text      string
+		citations []models.SourceRange
+	}{
+		{
+			name:      "single line",
+			item:      "完成原型开发 [L1]",
+			text:      "完成原型开发",
+			citations: []models.SourceRange{r(1, 1, 1000, 2000)},
+		},
+		{
+			name:      "ranges and lists are merged",
+			item:      "定下方案 [L0][L2-L3] [L1，L3]",
+			text:      "定下方案",
+			citations: []models.SourceRange{r(0, 3, 0, 4000)},
+		},
+		{
+			name:      "separate ranges",
+			item:      "两处讨论 [L0, L2–L2]",
+			text:      "两处讨论",
+			citations: []models.SourceRange{r(0, 0, 0, 1000), r(2, 2, 2000, 3000)},
+		},
+		{
+			name:      "merged utterance cites its source seqs",
+			item:      "收尾 [L4]",
+			text:      "收尾",
+			citations: []models.SourceRange{r(4, 5, 4000, 6000)},
+		},
+		{
+			name:      "other bracketed numbers are kept",
+			item:      "参考 [1] 和 [2024] 的数据",
+			text:      "参考 [1] 和 [2024] 的数据",
+			citations: []models.SourceRange{},
+		},
+		{
+			name:      "lines out of range are dropped",
+			item:      "越界 [L9][L3-L1]",
+			text:      "越界",
+			citations: []models.SourceRange{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sr := &models.SummaryResponse{Summary: "  - " + tt.item, Tasks: []string{tt.item}}
+			citations := extractCitations(sr, utterances)
+			if want := "  - " + tt.text; sr.Summary != want {
+				t.Errorf("summary = %q, want %q", sr.Summary, want)
+			}
+			if sr.Tasks[0] != tt.text {
+				t.Errorf("task = %q, want %q", sr.Tasks[0], tt.text)
+			}
+			want := [][]models.SourceRange{tt.citations}
+			if !reflect.DeepEqual(citations.Summary, want) || !reflect.DeepEqual(citations.Tasks, want) {
+				t.Errorf("citations = %+v and %+v, want %+v", citations.Summary, citations.Tasks, want)
+			}
+			if len(citations.Decisions) != 0 || citations.Decisions == nil {
+				t.Errorf("decisions = %+v, want an empty list", citations.Decisions)
+			}
+		})
+	}
+}
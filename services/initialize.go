@@ -18,6 +18,9 @@ import (
 
 func Init() {
 	bgCtx := context.Background()
+	if err := initRedaction(); err != nil {
+		log.Fatalf("failed to init redaction: %v", err)
+	}
 	if err := initSummaryChatModel(bgCtx); err != nil {
 		log.Fatalf("failed to init SummaryChatModel: %v", err)
 	}
@@ -36,6 +39,7 @@ func Init() {
 	if err := initHostMA(bgCtx, chatAgent, []*host.Specialist{taskManager, chatManager}); err != nil {
 		log.Fatalf("failed to init host multi-agent: %v", err)
 	}
+	initTranscriber()
 
 	log.Printf("✔ ChatModels and Agents initialized")
 }
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"meetingagent/config"
+	"meetingagent/models"
+)
+
+// jobPollInterval is the longest an idle worker waits before looking for due jobs again
+const jobPollInterval = 5 * time.Second
+
+// maxRetryBackoff caps the delay between two attempts of a job
+const maxRetryBackoff = 30 * time.Minute
+
+// jobHeartbeatInterval is how often a running job is touched to show that its process is alive
+const jobHeartbeatInterval = 30 * time.Second
+
+// jobStaleAfter is how long a running job may go untouched before it counts as
+// interrupted, e.g. by a crash, and is queued again
+const jobStaleAfter = 4 * jobHeartbeatInterval
+
+// JobQueue runs the background jobs stored in the database on a pool of workers.
+// A failed job is retried with exponential backoff until it runs out of attempts,
+// and the meeting's summary job state follows every step.
+type JobQueue struct {
+	jobs         models.JobRepository
+	meetings     models.MeetingRepository
+	participants models.ParticipantRepository
+	workers      int
+	maxAttempts  int
+	backoff      time.Duration
+	wake         chan struct{}
+}
+
+// NewJobQueue creates a queue over the given repositories; no worker runs until Start or Drain
+func NewJobQueue(jobs models.JobRepository, meetings models.MeetingRepository, participants models.ParticipantRepository, cfg config.JobsConfig) *JobQueue {
+	return &JobQueue{
+		jobs:         jobs,
+		meetings:     meetings,
+		participants: participants,
+		workers:      cfg.Workers,
+		maxAttempts:  cfg.MaxAttempts,
+		backoff:      time.Duration(cfg.RetryBackoffSeconds) * time.Second,
+		wake:         make(chan struct{}, cfg.Workers),
+	}
+}
+
+// Enqueue marks the meeting's summary as queued and adds a job of the given kind.
+// A job of that kind which is still waiting for the meeting is reused.
+func (q *JobQueue) Enqueue(kind string, meetingID int64) error {
+	// The state is set first so that it can't overwrite a worker that already picked the job up
+	if err := q.meetings.SetSummaryStatus(meetingID, models.SummaryQueued, ""); err != nil {
+		return err
+	}
+	if _, err := q.jobs.EnqueueJob(&models.Job{Kind: kind, MeetingID: meetingID, MaxAttempts: q.maxAttempts}); err != nil {
+		return err
+	}
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start queues the jobs interrupted by the previous shutdown again and starts the
+// workers, which run until ctx is cancelled
+func (q *JobQueue) Start(ctx context.Context) error {
+	if err := q.requeueStale(); err != nil {
+		return err
+	}
+	for i := 0; i < q.workers; i++ {
+		go q.work(ctx, false)
+	}
+	return nil
+}
+
+// Drain runs the workers until no job is queued or running anymore, including
+// retries that are not due yet and jobs running in another process. Jobs whose
+// process stopped without finishing them are run again once they are stale.
+func (q *JobQueue) Drain(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for i := 0; i < q.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work(ctx, true)
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// work runs due jobs one after another; with untilIdle it returns once no job is pending
+func (q *JobQueue) work(ctx context.Context, untilIdle bool) {
+	for ctx.Err() == nil {
+		job, err := q.jobs.ClaimJob(time.Now())
+		if err != nil {
+			log.Printf("Error claiming job: %v", err)
+		} else if job != nil {
+			q.run(ctx, job)
+			continue
+		}
+
+		if err == nil {
+			if err := q.requeueStale(); err != nil {
+				log.Printf("Error requeuing interrupted jobs: %v", err)
+			}
+		}
+		if untilIdle && err == nil {
+			pending, err := q.jobs.CountPendingJobs()
+			if err != nil {
+				log.Printf("Error counting pending jobs: %v", err)
+			} else if pending == 0 {
+				return
+			}
+		}
+
+		wait := jobPollInterval
+		if err == nil {
+			wait = q.idleWait()
+		}
+		select {
+		case <-ctx.Done():
+		case <-q.wake:
+		case <-time.After(wait):
+		}
+	}
+}
+
+// requeueStale queues the jobs that stopped being touched while running, which were
+// interrupted by the end of their process
+func (q *JobQueue) requeueStale() error {
+	recovered, err := q.jobs.RequeueRunningJobs(time.Now().Add(-jobStaleAfter))
+	if err != nil {
+		return err
+	}
+	for _, job := range recovered {
+		log.Printf("Resuming %s job %d for meeting %d", job.Kind, job.ID, job.MeetingID)
+		if err := q.meetings.SetSummaryStatus(job.MeetingID, models.SummaryQueued, job.LastError.String); err != nil {
+			log.Printf("Error recording summary status for meeting %d: %v", job.MeetingID, err)
+		}
+	}
+	return nil
+}
+
+// idleWait is how long a worker without a due job sleeps: until the next retry is due, at most jobPollInterval
+func (q *JobQueue) idleWait() time.Duration {
+	next, err := q.jobs.NextJobRunAt()
+	if err != nil || next == nil {
+		return jobPollInterval
+	}
+	wait := time.Until(*next)
+	if wait < 0 {
+		// Due but claimed by another worker meanwhile
+		return 0
+	}
+	if wait > jobPollInterval {
+		return jobPollInterval
+	}
+	return wait
+}
+
+// run executes a claimed job and records its outcome
+func (q *JobQueue) run(ctx context.Context, job *models.Job) {
+	if err := q.meetings.SetSummaryStatus(job.MeetingID, models.SummaryRunning, ""); err != nil {
+		log.Printf("Error recording summary status for meeting %d: %v", job.MeetingID, err)
+	}
+
+	// Touch the job while it runs, so that other processes don't take it for interrupted
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(jobHeartbeatInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := q.jobs.TouchJob(job.ID); err != nil {
+					log.Printf("Error touching job %d: %v", job.ID, err)
+				}
+			}
+		}
+	}()
+	err := q.execute(ctx, job)
+	close(stop)
+	if err == nil {
+		if err := q.jobs.CompleteJob(job.ID); err != nil {
+			log.Printf("Error completing job %d: %v", job.ID, err)
+		}
+		return
+	}
+	if ctx.Err() != nil {
+		// Shutting down: the job is left running and resumed by the next Start
+		return
+	}
+
+	status := models.SummaryFailed
+	if job.Attempts < job.MaxAttempts {
+		delay := q.retryDelay(job.Attempts)
+		log.Printf("%s job %d for meeting %d failed (attempt %d of %d), retrying in %v: %v",
+			job.Kind, job.ID, job.MeetingID, job.Attempts, job.MaxAttempts, delay, err)
+		if err := q.jobs.RetryJob(job.ID, time.Now().Add(delay), err.Error()); err != nil {
+			log.Printf("Error rescheduling job %d: %v", job.ID, err)
+		}
+		status = models.SummaryQueued
+	} else {
+		log.Printf("%s job %d for meeting %d failed after %d attempts: %v",
+			job.Kind, job.ID, job.MeetingID, job.Attempts, err)
+		if err := q.jobs.FailJob(job.ID, err.Error()); err != nil {
+			log.Printf("Error failing job %d: %v", job.ID, err)
+		}
+	}
+	if err := q.meetings.SetSummaryStatus(job.MeetingID, status, err.Error()); err != nil {
+		log.Printf("Error recording summary status for meeting %d: %v", job.MeetingID, err)
+	}
+}
+
+func (q *JobQueue) execute(ctx context.Context, job *models.Job) error {
+	switch job.Kind {
+	case models.JobTranscribe:
+		if err := TranscribeMeeting(ctx, q.meetings, job.MeetingID); err != nil {
+			return err
+		}
+		return q.Enqueue(models.JobSummarize, job.MeetingID)
+	case models.JobSummarize:
+		if err := SummarizeMeeting(ctx, q.meetings, q.participants, job.MeetingID); err != nil {
+			return err
+		}
+		return q.meetings.SetSummaryStatus(job.MeetingID, models.SummarySucceeded, "")
+	default:
+		return fmt.Errorf("unknown job kind %q", job.Kind)
+	}
+}
+
+// retryDelay doubles the configured backoff with every failed attempt
+func (q *JobQueue) retryDelay(attempts int) time.Duration {
+	delay := q.backoff
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
+}
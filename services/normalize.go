@@ -0,0 +1,77 @@
+package services
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"meetingagent/config"
+	"meetingagent/models"
+	"meetingagent/transcript"
+)
+
+// normalizeOptions combines the configured normalization with a meeting's own
+// settings. It returns false when normalization is disabled for the meeting.
+func normalizeOptions(meeting *models.Meeting) (transcript.NormalizeOptions, bool) {
+	nc := config.AppConfig.Normalization
+	settings := meeting.Normalization()
+
+	enabled := nc.Enabled
+	if settings.Enabled != nil {
+		enabled = *settings.Enabled
+	}
+	if !enabled {
+		return transcript.NormalizeOptions{}, false
+	}
+
+	opts := transcript.NormalizeOptions{
+		MergeAdjacent: nc.MergeAdjacent,
+		MaxGapMs:      int64(nc.MaxGapSeconds) * 1000,
+		StripFillers:  nc.StripFillers,
+		Fillers:       nc.Fillers,
+		Aliases:       make(map[string][]string),
+	}
+	if settings.MergeAdjacent != nil {
+		opts.MergeAdjacent = *settings.MergeAdjacent
+	}
+	if settings.MaxGapSeconds != nil {
+		opts.MaxGapMs = int64(*settings.MaxGapSeconds) * 1000
+	}
+	if settings.StripFillers != nil {
+		opts.StripFillers = *settings.StripFillers
+	}
+	for name, spellings := range nc.Aliases {
+		opts.Aliases[name] = append(opts.Aliases[name], spellings...)
+	}
+	for name, spellings := range settings.Aliases {
+		opts.Aliases[name] = append(opts.Aliases[name], spellings...)
+	}
+	return opts, true
+}
+
+// NormalizeMeeting normalizes a meeting's utterances and stores the result next to
+// the originals, which are left untouched. The stored result is cleared when
+// normalization is disabled or the meeting has no parsed utterances.
+func NormalizeMeeting(repo models.MeetingRepository, meeting *models.Meeting) error {
+	var normalized []models.Utterance
+	if opts, ok := normalizeOptions(meeting); ok {
+		utterances, err := repo.ListUtterances(meeting.ID)
+		if err != nil {
+			return fmt.Errorf("failed to load utterances: %w", err)
+		}
+		normalized = transcript.Normalize(utterances, opts)
+	}
+
+	meeting.NormalizedUtterancesJSON = sql.NullString{}
+	if len(normalized) > 0 {
+		normalizedJSON, err := json.Marshal(normalized)
+		if err != nil {
+			return fmt.Errorf("failed to encode normalized utterances: %w", err)
+		}
+		meeting.NormalizedUtterancesJSON = sql.NullString{String: string(normalizedJSON), Valid: true}
+	}
+	if err := repo.SetNormalization(meeting.ID, meeting.NormalizationJSON, meeting.NormalizedUtterancesJSON); err != nil {
+		return fmt.Errorf("failed to store normalized utterances: %w", err)
+	}
+	return nil
+}
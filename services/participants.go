@@ -0,0 +1,168 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"meetingagent/models"
+)
+
+// participantIndex maps lower-cased display names and aliases to participant IDs.
+// Names claimed by more than one participant are left out since they can't be matched reliably.
+func participantIndex(participants []models.Participant) map[string]int64 {
+	index := make(map[string]int64)
+	ambiguous := make(map[string]bool)
+	for _, p := range participants {
+		for _, name := range append([]string{p.DisplayName}, p.Aliases()...) {
+			key := strings.ToLower(strings.TrimSpace(name))
+			if key == "" {
+				continue
+			}
+			if id, ok := index[key]; ok && id != p.ID {
+				ambiguous[key] = true
+			}
+			index[key] = p.ID
+		}
+	}
+	for key := range ambiguous {
+		delete(index, key)
+	}
+	return index
+}
+
+// speakerNames returns the distinct speaker names of a meeting's utterances, sorted
+func speakerNames(repo models.MeetingRepository, meetingID int64) ([]string, error) {
+	utterances, err := repo.ListUtterances(meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load utterances: %w", err)
+	}
+	seen := make(map[string]bool)
+	var names []string
+	for _, u := range utterances {
+		name := strings.TrimSpace(u.Speaker)
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// LinkMeetingSpeakers matches a meeting's speakers to registered participants by
+// display name or alias, ignoring case. Manually set links are left alone.
+func LinkMeetingSpeakers(repo models.MeetingRepository, participants models.ParticipantRepository, meetingID int64) error {
+	names, err := speakerNames(repo, meetingID)
+	if err != nil {
+		return err
+	}
+	registered, err := participants.ListParticipants()
+	if err != nil {
+		return fmt.Errorf("failed to load participants: %w", err)
+	}
+
+	index := participantIndex(registered)
+	var links []models.MeetingSpeaker
+	for _, name := range names {
+		if id, ok := index[strings.ToLower(name)]; ok {
+			links = append(links, models.MeetingSpeaker{
+				MeetingID:     meetingID,
+				Speaker:       name,
+				ParticipantID: sql.NullInt64{Int64: id, Valid: true},
+			})
+		}
+	}
+	if err := participants.ReplaceMatchedSpeakers(meetingID, links); err != nil {
+		return fmt.Errorf("failed to link speakers: %w", err)
+	}
+	return nil
+}
+
+// RelinkParticipantSpeakers matches the speakers again after a participant changed.
+// Only meetings in which one of the participant's old or new names spoke, or that
+// have a speaker linked to it, are affected.
+func RelinkParticipantSpeakers(repo models.MeetingRepository, participants models.ParticipantRepository, before, after *models.Participant) error {
+	var names []string
+	for _, p := range []*models.Participant{before, after} {
+		if p != nil {
+			names = append(names, p.DisplayName)
+			names = append(names, p.Aliases()...)
+		}
+	}
+	meetingIDs, err := participants.ListSpeakerMeetingIDs(after.ID, names)
+	if err != nil {
+		return err
+	}
+	for _, meetingID := range meetingIDs {
+		if err := LinkMeetingSpeakers(repo, participants, meetingID); err != nil {
+			return fmt.Errorf("meeting %d: %w", meetingID, err)
+		}
+	}
+	return nil
+}
+
+// MeetingSpeakers lists every speaker of a meeting with its participant link, if any
+func MeetingSpeakers(repo models.MeetingRepository, participants models.ParticipantRepository, meetingID int64) ([]models.MeetingSpeaker, error) {
+	names, err := speakerNames(repo, meetingID)
+	if err != nil {
+		return nil, err
+	}
+	links, err := participants.ListMeetingSpeakers(meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load speaker links: %w", err)
+	}
+
+	byName := make(map[string]models.MeetingSpeaker, len(links))
+	for _, link := range links {
+		byName[link.Speaker] = link
+	}
+	speakers := make([]models.MeetingSpeaker, 0, len(names))
+	for _, name := range names {
+		link, ok := byName[name]
+		if !ok {
+			link = models.MeetingSpeaker{MeetingID: meetingID, Speaker: name}
+		}
+		speakers = append(speakers, link)
+	}
+	return speakers, nil
+}
+
+// LinkedParticipantNames maps each speaker of a meeting that is linked to a participant
+// to the participant's display name
+func LinkedParticipantNames(participants models.ParticipantRepository, meetingID int64) (map[string]string, error) {
+	links, err := participants.ListMeetingSpeakers(meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load speaker links: %w", err)
+	}
+	linked := make(map[string]string)
+	for _, link := range links {
+		if !link.ParticipantID.Valid {
+			continue
+		}
+		participant, err := participants.GetParticipantByID(link.ParticipantID.Int64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load participant: %w", err)
+		}
+		if participant != nil {
+			linked[link.Speaker] = participant.DisplayName
+		}
+	}
+	return linked, nil
+}
+
+// ParticipantSpeakerNames returns the speaker names linked to a participant in a meeting
+func ParticipantSpeakerNames(participants models.ParticipantRepository, meetingID, participantID int64) ([]string, error) {
+	links, err := participants.ListMeetingSpeakers(meetingID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load speaker links: %w", err)
+	}
+	var names []string
+	for _, link := range links {
+		if link.ParticipantID.Valid && link.ParticipantID.Int64 == participantID {
+			names = append(names, link.Speaker)
+		}
+	}
+	return names, nil
+}
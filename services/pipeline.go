@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"unicode"
+
+	"meetingagent/config"
+	"meetingagent/models"
+)
+
+// maxNameAttempts bounds how many numbered names are tried when a meeting name is taken
+const maxNameAttempts = 100
+
+// CreateMeetingWithUniqueName creates the meeting with its utterances, numbering its name
+// ("name (2)", "name (3)", ...) when a different meeting already uses it. The original
+// filename is kept in AudioFilename.
+func CreateMeetingWithUniqueName(repo models.MeetingRepository, meeting *models.Meeting, utterances []models.Utterance) (int64, error) {
+	baseName := meeting.Name
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 {
+			meeting.Name = fmt.Sprintf("%s (%d)", baseName, attempt)
+		}
+		newID, err := repo.CreateMeetingWithUtterances(meeting, utterances)
+		if err == nil {
+			return newID, nil
+		}
+		if !strings.Contains(err.Error(), "UNIQUE constraint failed") || attempt >= maxNameAttempts {
+			return 0, err
+		}
+	}
+}
+
+// SummarizeMeeting generates the summary, tasks with their details and chapters of a stored meeting and saves them back
+func SummarizeMeeting(ctx context.Context, repo models.MeetingRepository, participants models.ParticipantRepository, meetingID int64) error {
+	meeting, err := repo.GetMeetingByID(meetingID)
+	if err != nil {
+		return fmt.Errorf("failed to load meeting: %w", err)
+	}
+	if meeting == nil {
+		return fmt.Errorf("meeting %d not found", meetingID)
+	}
+
+	// Speakers are linked to registered participants whenever the transcript is summarized
+	if err := LinkMeetingSpeakers(repo, participants, meetingID); err != nil {
+		return err
+	}
+
+	if err := NormalizeMeeting(repo, meeting); err != nil {
+		return err
+	}
+
+	utterances, err := repo.ListUtterances(meetingID)
+	if err != nil {
+		return fmt.Errorf("failed to load utterances: %w", err)
+	}
+
+	sr, usage, err := GetMeetingSummary(ctx, meeting, utterances)
+	if err != nil {
+		return err
+	}
+	// Without chapters or task details the summary is still stored; a meeting without
+	// chapters lists none, and its plain tasks serve instead of task details
+	chapters, chapterUsage, err := GetMeetingChapters(ctx, meeting, utterances)
+	usage.Add(chapterUsage)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		log.Printf("Error generating chapters for meeting %d: %v", meetingID, err)
+	}
+	chaptersJSON, err := json.Marshal(chapters)
+	if err != nil {
+		return fmt.Errorf("failed to marshal chapters: %w", err)
+	}
+	meeting.ChaptersJSON = sql.NullString{String: string(chaptersJSON), Valid: chapters != nil}
+
+	linked, err := LinkedParticipantNames(participants, meetingID)
+	if err != nil {
+		return err
+	}
+	taskItems, taskItemUsage, err := GetTaskItems(ctx, meeting, utterances, linked, sr.Tasks)
+	usage.Add(taskItemUsage)
+	if err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		log.Printf("Error extracting task details for meeting %d: %v", meetingID, err)
+	}
+	sr.TaskItems = taskItems
+
+	// Every summary is kept as a new version, which becomes the current one
+	if err := applySummary(meeting, sr); err != nil {
+		return err
+	}
+	options := meeting.SummaryOptions()
+	version := &models.SummaryVersion{
+		MeetingID:     meetingID,
+		SummaryText:   sr.Summary,
+		TasksJSON:     meeting.TasksJSON,
+		SectionsJSON:  meeting.SectionsJSON,
+		CitationsJSON: meeting.CitationsJSON,
+		TaskItemsJSON: meeting.TaskItemsJSON,
+		ChaptersJSON:  meeting.ChaptersJSON,
+		Model:         sql.NullString{String: config.AppConfig.Summary.Model, Valid: true},
+		Template:      sql.NullString{String: options.Template, Valid: options.Template != ""},
+		Instructions:  sql.NullString{String: options.Instructions, Valid: options.Instructions != ""},
+		Usage:         usage,
+	}
+	if _, err := repo.SaveSummary(meeting, version); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PinSummaryVersion makes a stored summary version the meeting's current summary again,
+// with the chapters generated along with it
+func PinSummaryVersion(repo models.MeetingRepository, meeting *models.Meeting, version *models.SummaryVersion) error {
+	sr := &models.SummaryResponse{
+		Summary:         version.SummaryText,
+		Tasks:           version.Tasks(),
+		SummarySections: version.Sections(),
+		Citations:       version.Citations(),
+		TaskItems:       version.TaskItems(),
+	}
+	if err := applySummary(meeting, sr); err != nil {
+		return err
+	}
+	meeting.ChaptersJSON = version.ChaptersJSON
+	return repo.PinSummary(meeting, version.Version)
+}
+
+// applySummary sets sr as the meeting's summary, storing the summary text and tasks
+// separately. Tasks that were completed in the previous summary stay completed, new
+// ones start out incomplete.
+func applySummary(meeting *models.Meeting, sr *models.SummaryResponse) error {
+	jsonByte, err := json.Marshal(sr)
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary response: %w", err)
+	}
+	tasksJSON, err := json.Marshal(sr.Tasks)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tasks: %w", err)
+	}
+	sectionsJSON, err := json.Marshal(sr.SummarySections)
+	if err != nil {
+		return fmt.Errorf("failed to marshal summary sections: %w", err)
+	}
+	citationsJSON, err := json.Marshal(sr.Citations)
+	if err != nil {
+		return fmt.Errorf("failed to marshal citations: %w", err)
+	}
+	taskItemsJSON, err := json.Marshal(sr.TaskItems)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task items: %w", err)
+	}
+
+	meeting.TasksStatusNum = carryTaskStatus(meeting.Tasks(), meeting.TasksStatusNum, sr.Tasks)
+	meeting.SummaryText = sql.NullString{String: sr.Summary, Valid: true}
+	meeting.TasksJSON = sql.NullString{String: string(tasksJSON), Valid: true}
+	meeting.SectionsJSON = sql.NullString{String: string(sectionsJSON), Valid: true}
+	meeting.CitationsJSON = sql.NullString{String: string(citationsJSON), Valid: sr.Citations != nil}
+	meeting.TaskItemsJSON = sql.NullString{String: string(taskItemsJSON), Valid: sr.TaskItems != nil}
+	meeting.ChatHistory = sql.NullString{String: string(jsonByte), Valid: true}
+	return nil
+}
+
+// maxTaskFlags is the number of tasks whose completion fits in TasksStatusNum
+const maxTaskFlags = 63
+
+// carryTaskStatus returns the completion flags of newTasks, marking each task that
+// matches a completed one of oldTasks as completed. Tasks match when they are equal
+// ignoring case, spacing and punctuation, and each old task matches at most once.
+func carryTaskStatus(oldTasks []string, oldStatus int64, newTasks []string) int64 {
+	completed := make(map[string]int)
+	for i, task := range oldTasks {
+		if i < maxTaskFlags && oldStatus&(1<<i) != 0 {
+			completed[taskKey(task)]++
+		}
+	}
+
+	var status int64
+	for i, task := range newTasks {
+		if i >= maxTaskFlags {
+			break
+		}
+		key := taskKey(task)
+		if completed[key] > 0 {
+			completed[key]--
+			status |= 1 << i
+		}
+	}
+	return status
+}
+
+// taskKey reduces a task to its letters and digits in lower case
+func taskKey(task string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, task)
+}
+
+// TranscribeMeeting runs the configured Transcriber on a meeting's stored audio
+// and saves the resulting transcript and utterances
+func TranscribeMeeting(ctx context.Context, repo models.MeetingRepository, meetingID int64) error {
+	if DefaultTranscriber == nil {
+		return fmt.Errorf("no transcriber configured")
+	}
+
+	meeting, err := repo.GetMeetingByID(meetingID)
+	if err != nil {
+		return fmt.Errorf("failed to load meeting: %w", err)
+	}
+	if meeting == nil {
+		return fmt.Errorf("meeting %d not found", meetingID)
+	}
+	if !meeting.AudioPath.Valid || meeting.AudioPath.String == "" {
+		return fmt.Errorf("meeting %d has no stored audio", meetingID)
+	}
+
+	doc, err := DefaultTranscriber.Transcribe(ctx, meeting.AudioPath.String)
+	if err != nil {
+		return fmt.Errorf("failed to transcribe audio: %w", err)
+	}
+
+	// Only the transcript is written, as the meeting may have been changed during the run.
+	// The content hash stays that of the audio, so that uploading it again is detected.
+	if err := repo.SetTranscript(meetingID, doc.Text, meeting.ContentHash); err != nil {
+		return fmt.Errorf("failed to store transcript: %w", err)
+	}
+	if err := repo.SaveUtterances(meetingID, doc.Utterances); err != nil {
+		return fmt.Errorf("failed to store utterances: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"meetingagent/models"
+	"meetingagent/transcript"
+)
+
+func TestCarryTaskStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		oldTasks  []string
+		oldStatus int64
+		newTasks  []string
+		want      int64
+	}{
+		{
+			name:     "nothing completed",
+			oldTasks: []string{"a", "b"},
+			newTasks: []string{"a", "b"},
+			want:     0,
+		},
+		{
+			name:      "completed tasks follow their new position",
+			oldTasks:  []string{"写周报", "Finish the prototype."},
+			oldStatus: 0b10,
+			newTasks:  []string{"订会议室", "finish the prototype", "写周报"},
+			want:      0b010,
+		},
+		{
+			name:      "reworded tasks are not carried",
+			oldTasks:  []string{"下周五前完成原型"},
+			oldStatus: 0b1,
+			newTasks:  []string{"下周三前完成原型"},
+			want:      0,
+		},
+		{
+			name:      "each old task matches once",
+			oldTasks:  []string{"写周报", "写周报"},
+			oldStatus: 0b01,
+			newTasks:  []string{"写周报", "写周报"},
+			want:      0b01,
+		},
+		{
+			name:      "tasks past the flag limit are ignored",
+			oldTasks:  append(make([]string, maxTaskFlags), "late"),
+			oldStatus: -1,
+			newTasks:  []string{"late"},
+			want:      0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := carryTaskStatus(tt.oldTasks, tt.oldStatus, tt.newTasks); got != tt.want {
+				t.Errorf("carryTaskStatus() = %b, want %b", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeTranscriber returns a fixed document or error and records the audio it was given
+type fakeTranscriber struct {
+	doc       *transcript.Document
+	err       error
+	audioPath string
+}
+
+func (f *fakeTranscriber) Transcribe(ctx context.Context, audioPath string) (*transcript.Document, error) {
+	f.audioPath = audioPath
+	return f.doc, f.err
+}
+
+// fakeMeetingRepo keeps a single meeting in memory; the methods TranscribeMeeting
+// doesn't use are left to the nil embedded interface
+type fakeMeetingRepo struct {
+	models.MeetingRepository
+	meeting     *models.Meeting
+	transcript  sql.NullString
+	contentHash sql.NullString
+	utterances  []models.Utterance
+}
+
+func (r *fakeMeetingRepo) GetMeetingByID(id int64) (*models.Meeting, error) {
+	if r.meeting == nil || r.meeting.ID != id {
+		return nil, nil
+	}
+	return r.meeting, nil
+}
+
+func (r *fakeMeetingRepo) SetTranscript(meetingID int64, transcript string, contentHash sql.NullString) error {
+	r.transcript = sql.NullString{String: transcript, Valid: true}
+	r.contentHash = contentHash
+	return nil
+}
+
+func (r *fakeMeetingRepo) SaveUtterances(meetingID int64, utterances []models.Utterance) error {
+	r.utterances = utterances
+	return nil
+}
+
+func TestTranscribeMeeting(t *testing.T) {
+	doc := &transcript.Document{
+		Format: transcript.FormatText,
+		Text:   "00:00:00-00:00:05 Lily: 开始吧\n",
+		Utterances: []models.Utterance{
+			{Seq: 0, Speaker: "Lily", StartMs: 0, EndMs: 5000, Text: "开始吧"},
+		},
+	}
+	audio := sql.NullString{String: "/data/audio/1.wav", Valid: true}
+	audioHash := sql.NullString{String: "audiohash", Valid: true}
+	tests := []struct {
+		name        string
+		transcriber *fakeTranscriber
+		meeting     *models.Meeting
+		err         string // Substring of the expected error; empty for success
+	}{
+		{
+			name:        "stores transcript and utterances",
+			transcriber: &fakeTranscriber{doc: doc},
+			meeting:     &models.Meeting{ID: 1, AudioPath: audio, ContentHash: audioHash},
+		},
+		{
+			name:    "no transcriber",
+			meeting: &models.Meeting{ID: 1, AudioPath: audio},
+			err:     "no transcriber configured",
+		},
+		{
+			name:        "meeting not found",
+			transcriber: &fakeTranscriber{doc: doc},
+			err:         "meeting 1 not found",
+		},
+		{
+			name:        "no audio",
+			transcriber: &fakeTranscriber{doc: doc},
+			meeting:     &models.Meeting{ID: 1},
+			err:         "has no stored audio",
+		},
+		{
+			name:        "transcriber fails",
+			transcriber: &fakeTranscriber{err: errors.New("exit status 1")},
+			meeting:     &models.Meeting{ID: 1, AudioPath: audio},
+			err:         "failed to transcribe audio: exit status 1",
+		},
+	}
+	defer SetTranscriber(DefaultTranscriber)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// A nil *fakeTranscriber would not be a nil Transcriber
+			SetTranscriber(nil)
+			if tt.transcriber != nil {
+				SetTranscriber(tt.transcriber)
+			}
+			repo := &fakeMeetingRepo{meeting: tt.meeting}
+
+			err := TranscribeMeeting(context.Background(), repo, 1)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.err)
+				}
+				if repo.transcript.Valid || repo.utterances != nil {
+					t.Errorf("transcript or utterances were stored on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.transcriber.audioPath != audio.String {
+				t.Errorf("transcribed %q, want %q", tt.transcriber.audioPath, audio.String)
+			}
+			if !repo.transcript.Valid || repo.transcript.String != doc.Text {
+				t.Errorf("transcript = %+v, want %q", repo.transcript, doc.Text)
+			}
+			if repo.contentHash != audioHash {
+				t.Errorf("content hash = %+v, want that of the audio %+v", repo.contentHash, audioHash)
+			}
+			if len(repo.utterances) != 1 || repo.utterances[0].Text != "开始吧" {
+				t.Errorf("utterances = %+v, want those of the document", repo.utterances)
+			}
+		})
+	}
+}
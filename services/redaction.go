@@ -0,0 +1,57 @@
+package services
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+
+	"meetingagent/config"
+	"meetingagent/models"
+	"meetingagent/redact"
+)
+
+// redactionRules are the compiled rules from the redaction config
+var redactionRules []redact.Rule
+
+// initRedaction compiles the configured redaction rules
+func initRedaction() error {
+	rc := config.AppConfig.Redaction
+	if !rc.Enabled {
+		return nil
+	}
+	if len(rc.Rules) == 0 {
+		redactionRules = redact.DefaultRules()
+		log.Printf("✔ Redaction enabled with built-in rules")
+		return nil
+	}
+
+	rules := make([]redact.Rule, 0, len(rc.Rules))
+	for _, r := range rc.Rules {
+		pattern, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid redaction rule %s: %v", r.Name, err)
+		}
+		rules = append(rules, redact.Rule{Name: r.Name, Pattern: pattern})
+	}
+	redactionRules = rules
+	log.Printf("✔ Redaction enabled with %d rules", len(rules))
+	return nil
+}
+
+// NewMeetingRedactor returns the redactor to apply to a meeting's content before model
+// calls, combining the configured rules and lists with the meeting's own lists.
+// It returns nil, which leaves text unchanged, when redaction is disabled.
+func NewMeetingRedactor(meeting *models.Meeting) *redact.Redactor {
+	rc := config.AppConfig.Redaction
+	if !rc.Enabled {
+		return nil
+	}
+	allow := append(append([]string{}, rc.Allow...), meeting.RedactAllow()...)
+	deny := append(append([]string{}, rc.Deny...), meeting.RedactDeny()...)
+	return redact.New(redactionRules, allow, deny)
+}
+
+// RestoreOutput reports whether placeholders may be mapped back in displayed model output
+func RestoreOutput() bool {
+	return config.AppConfig.Redaction.RestoreOutput
+}
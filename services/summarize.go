@@ -0,0 +1,295 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"meetingagent/config"
+	"meetingagent/models"
+	"meetingagent/redact"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// summaryJSONFormat is the reply format requested from the summary model
+const summaryJSONFormat = `{"summary": "...", "tasks": ["..."], "decisions": ["..."], "open_questions": ["..."], "risks": ["..."], "topics": ["..."]}`
+
+// summaryFormatMessage follows the configured system message and describes the sections of the reply
+const summaryFormatMessage = `请只输出一个 JSON 对象，格式为 ` + summaryJSONFormat + `。
+summary 为会议总结；tasks 为待办事项；decisions 为会议做出的决定；open_questions 为尚未解决的问题；
+risks 为提到的风险或阻碍；topics 为讨论的主题列表。没有内容的部分输出空数组。`
+
+// defaultMergeMessage is the system message for merging partial summaries when none is configured
+const defaultMergeMessage = `你将收到同一场会议按时间顺序分段生成的多份部分总结（JSON 数组）。
+请将它们合并为一份完整的会议总结：summary 连贯地概括整场会议，其余各部分分别合并并去除重复。
+只输出 JSON，格式为 ` + summaryJSONFormat + `。`
+
+// repairMessage asks the model to correct a reply that failed validation; it is given the error and the expected format
+const repairMessage = `你的回复无法解析：%v。
+请只输出一个 JSON 对象，不要包含其他文字，格式为 %s。`
+
+// summaryInstructionsPrefix introduces the additional instructions given for a meeting's summary
+const summaryInstructionsPrefix = "生成总结时请额外遵循以下要求：\n"
+
+// summaryPrompt is the context shared by all requests that summarize one meeting
+type summaryPrompt struct {
+	system      []*schema.Message // System messages of requests that summarize transcript
+	merge       []*schema.Message // System messages of requests that merge partial summaries
+	meetingInfo string
+	usage       models.TokenUsage // Tokens spent by all requests so far
+}
+
+// newSummaryPrompt builds the prompt for a meeting from its summary options: the
+// template's system message replaces the configured one, and additional instructions
+// are passed to every request. With cite, the model is asked to mark the numbered
+// transcript lines each item is based on.
+func newSummaryPrompt(options models.SummaryOptions, meetingInfo string, redactor *redact.Redactor, cite bool) (*summaryPrompt, error) {
+	templateMessage, ok := config.AppConfig.GetSummaryTemplateMessage(options.Template)
+	if !ok {
+		return nil, fmt.Errorf("unknown summary template %q", options.Template)
+	}
+	mergeMessage := config.AppConfig.Summary.MergeMessage
+	if mergeMessage == "" {
+		mergeMessage = defaultMergeMessage
+	}
+
+	prompt := &summaryPrompt{
+		system: []*schema.Message{
+			templateMessage,
+			{
+				Role:    schema.System,
+				Content: summaryFormatMessage,
+			},
+		},
+		merge: []*schema.Message{
+			{
+				Role:    schema.System,
+				Content: mergeMessage,
+			},
+		},
+		meetingInfo: meetingInfo,
+	}
+	if cite {
+		citation := &schema.Message{
+			Role:    schema.System,
+			Content: summaryCitationMessage,
+		}
+		prompt.system = append(prompt.system, citation)
+		prompt.merge = append(prompt.merge, citation)
+	}
+	if options.Instructions != "" {
+		instructions := &schema.Message{
+			Role:    schema.System,
+			Content: summaryInstructionsPrefix + redactor.Redact(options.Instructions),
+		}
+		prompt.system = append(prompt.system, instructions)
+		prompt.merge = append(prompt.merge, instructions)
+	}
+	return prompt, nil
+}
+
+// EstimateTokens roughly estimates the tokens in text without a tokenizer:
+// each CJK character counts as one token and other text as one token per four bytes.
+func EstimateTokens(text string) int {
+	cjk, other := 0, 0
+	for _, r := range text {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			cjk++
+		} else {
+			other += utf8.RuneLen(r)
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// chunkLines packs lines into chunks of at most budget estimated tokens.
+// A single line over the budget is cut into pieces on its own.
+func chunkLines(lines []string, budget int) []string {
+	var chunks []string
+	var current strings.Builder
+	currentTokens := 0
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+			current.Reset()
+			currentTokens = 0
+		}
+	}
+
+	for _, line := range lines {
+		tokens := EstimateTokens(line) + 1
+		if tokens > budget {
+			flush()
+			// Every rune is at most one estimated token, so budget runes always fit
+			runes := []rune(line)
+			for len(runes) > 0 {
+				n := min(budget, len(runes))
+				chunks = append(chunks, string(runes[:n]))
+				runes = runes[n:]
+			}
+			continue
+		}
+		if currentTokens+tokens > budget {
+			flush()
+		}
+		current.WriteString(line)
+		current.WriteByte('\n')
+		currentTokens += tokens
+	}
+	flush()
+	return chunks
+}
+
+// summarizeTranscript runs the summary prompt on a (redacted) piece of transcript
+func summarizeTranscript(ctx context.Context, prompt *summaryPrompt, transcriptText string) (*models.SummaryResponse, error) {
+	messages := append([]*schema.Message{}, prompt.system...)
+	messages = append(messages,
+		&schema.Message{
+			Role:    schema.User,
+			Content: prompt.meetingInfo,
+		},
+		&schema.Message{
+			Role:    schema.User,
+			Content: transcriptText,
+		},
+	)
+	return generateSummary(ctx, &prompt.usage, messages)
+}
+
+// mapReduceSummary summarizes each chunk of a long transcript and merges the partial summaries
+func mapReduceSummary(ctx context.Context, redactor *redact.Redactor, prompt *summaryPrompt, chunks []string, budget int) (*models.SummaryResponse, error) {
+	partials := make([]*models.SummaryResponse, 0, len(chunks))
+	for i, chunk := range chunks {
+		header := fmt.Sprintf("以下是会议记录的第 %d/%d 部分：\n", i+1, len(chunks))
+		partial, err := summarizeTranscript(ctx, prompt, header+redactor.Redact(chunk))
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize part %d/%d: %w", i+1, len(chunks), err)
+		}
+		partials = append(partials, partial)
+	}
+	return mergeSummaries(ctx, prompt, partials, budget)
+}
+
+// mergeSummaries merges partial summaries in order. When they don't fit in one
+// request, consecutive groups are merged first and the results merged again.
+func mergeSummaries(ctx context.Context, prompt *summaryPrompt, partials []*models.SummaryResponse, budget int) (*models.SummaryResponse, error) {
+	if len(partials) == 1 {
+		return partials[0], nil
+	}
+
+	var groups [][]*models.SummaryResponse
+	var group []*models.SummaryResponse
+	groupTokens := 0
+	for _, p := range partials {
+		encoded, err := json.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal partial summary: %w", err)
+		}
+		tokens := EstimateTokens(string(encoded))
+		if len(group) > 0 && groupTokens+tokens > budget {
+			groups = append(groups, group)
+			group, groupTokens = nil, 0
+		}
+		group = append(group, p)
+		groupTokens += tokens
+	}
+	groups = append(groups, group)
+
+	if len(groups) == len(partials) {
+		// Not even two partial summaries fit together; merge pairs anyway rather than loop forever
+		groups = groups[:0]
+		for i := 0; i < len(partials); i += 2 {
+			groups = append(groups, partials[i:min(i+2, len(partials))])
+		}
+	}
+
+	merged := make([]*models.SummaryResponse, 0, len(groups))
+	for _, g := range groups {
+		if len(g) == 1 {
+			merged = append(merged, g[0])
+			continue
+		}
+		m, err := mergeSummaryGroup(ctx, prompt, g)
+		if err != nil {
+			return nil, err
+		}
+		merged = append(merged, m)
+	}
+	return mergeSummaries(ctx, prompt, merged, budget)
+}
+
+// mergeSummaryGroup asks the model to merge consecutive partial summaries into one
+func mergeSummaryGroup(ctx context.Context, prompt *summaryPrompt, partials []*models.SummaryResponse) (*models.SummaryResponse, error) {
+	encoded, err := json.Marshal(partials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal partial summaries: %w", err)
+	}
+	messages := append([]*schema.Message{}, prompt.merge...)
+	messages = append(messages,
+		&schema.Message{
+			Role:    schema.User,
+			Content: prompt.meetingInfo,
+		},
+		&schema.Message{
+			Role:    schema.User,
+			Content: "各部分总结：\n" + string(encoded),
+		},
+	)
+	merged, err := generateSummary(ctx, &prompt.usage, messages)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge partial summaries: %w", err)
+	}
+	return merged, nil
+}
+
+// generateSummary sends the messages to the summary model and parses its JSON reply
+func generateSummary(ctx context.Context, usage *models.TokenUsage, messages []*schema.Message) (*models.SummaryResponse, error) {
+	var summaryResponse *models.SummaryResponse
+	err := generateJSON(ctx, usage, messages, summaryJSONFormat, func(content string) (err error) {
+		summaryResponse, err = parseSummaryResponse(content)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate summary: %w", err)
+	}
+	return summaryResponse, nil
+}
+
+// generateJSON sends the messages to the summary model until parse accepts its reply.
+// A reply that can't be parsed is sent back with the error and the expected format,
+// up to the configured number of repair attempts. A failed request is reported as such
+// rather than as an invalid reply. The tokens of every request are added to usage.
+func generateJSON(ctx context.Context, usage *models.TokenUsage, messages []*schema.Message, format string, parse func(content string) error) error {
+	repairs := config.AppConfig.Summary.MaxRepairAttempts
+	for attempt := 0; ; attempt++ {
+		response, err := SummaryChatModel.Generate(ctx, messages, model.WithTemperature(0.8))
+		if err != nil {
+			return fmt.Errorf("model request failed: %w", err)
+		}
+		if meta := response.ResponseMeta; meta != nil && meta.Usage != nil {
+			usage.Add(models.TokenUsage{
+				PromptTokens:     meta.Usage.PromptTokens,
+				CompletionTokens: meta.Usage.CompletionTokens,
+				TotalTokens:      meta.Usage.TotalTokens,
+			})
+		}
+
+		err = parse(response.Content)
+		if err == nil {
+			return nil
+		}
+		if attempt >= repairs {
+			return fmt.Errorf("invalid reply after %d attempts: %w", attempt+1, err)
+		}
+
+		messages = append(messages,
+			&schema.Message{Role: schema.Assistant, Content: response.Content},
+			&schema.Message{Role: schema.User, Content: fmt.Sprintf(repairMessage, err, format)},
+		)
+	}
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEstimateTokens(t *testing.T) {
+	tests := []struct {
+		text string
+		want int
+	}{
+		{"", 0},
+		{"hello world", 3},
+		{"会议总结", 4},
+		{"会议abc", 3},
+	}
+	for _, tt := range tests {
+		if got := EstimateTokens(tt.text); got != tt.want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestChunkLines(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []string
+		budget int
+		want   []string
+	}{
+		{
+			name:   "no lines",
+			budget: 10,
+		},
+		{
+			name:   "everything fits",
+			lines:  []string{"一二三", "四五六"},
+			budget: 10,
+			want:   []string{"一二三\n四五六\n"},
+		},
+		{
+			name:   "split on line boundaries",
+			lines:  []string{"一二三", "四五六", "七八九"},
+			budget: 10,
+			want:   []string{"一二三\n四五六\n", "七八九\n"},
+		},
+		{
+			name:   "line over the budget is cut on its own",
+			lines:  []string{"ab", "一二三四五六七", "cd"},
+			budget: 3,
+			want:   []string{"ab\n", "一二三", "四五六", "七", "cd\n"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := chunkLines(tt.lines, tt.budget); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chunkLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
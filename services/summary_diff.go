@@ -0,0 +1,108 @@
+package services
+
+import (
+	"meetingagent/models"
+)
+
+// minChangeSimilarity is how similar a removed and an added item must be to count as one changed item
+const minChangeSimilarity = 0.5
+
+// DiffSummaryVersions compares two summary versions of a meeting section by section
+func DiffSummaryVersions(from, to *models.SummaryVersion) models.SummaryDiff {
+	fromSections, toSections := from.Sections(), to.Sections()
+	return models.SummaryDiff{
+		From:           from.Version,
+		To:             to.Version,
+		SummaryChanged: from.SummaryText != to.SummaryText,
+		Tasks:          diffList(from.Tasks(), to.Tasks()),
+		Decisions:      diffList(fromSections.Decisions, toSections.Decisions),
+		OpenQuestions:  diffList(fromSections.OpenQuestions, toSections.OpenQuestions),
+		Risks:          diffList(fromSections.Risks, toSections.Risks),
+		Topics:         diffList(fromSections.Topics, toSections.Topics),
+	}
+}
+
+// diffList compares two versions of a list. Items equal apart from case, spacing and
+// punctuation are unchanged; the remaining old and new items are paired into changed
+// items by similarity, most similar first, and the rest are removed or added.
+func diffList(oldItems, newItems []string) models.ListDiff {
+	unmatched := make(map[string][]int)
+	for i, item := range oldItems {
+		key := taskKey(item)
+		unmatched[key] = append(unmatched[key], i)
+	}
+	oldUsed := make([]bool, len(oldItems))
+	newUsed := make([]bool, len(newItems))
+	for i, item := range newItems {
+		key := taskKey(item)
+		if indexes := unmatched[key]; len(indexes) > 0 {
+			oldUsed[indexes[0]] = true
+			newUsed[i] = true
+			unmatched[key] = indexes[1:]
+		}
+	}
+
+	diff := models.ListDiff{Added: []string{}, Removed: []string{}, Changed: []models.ItemChange{}}
+	for {
+		bestOld, bestNew, best := -1, -1, minChangeSimilarity
+		for i, oldItem := range oldItems {
+			if oldUsed[i] {
+				continue
+			}
+			for j, newItem := range newItems {
+				if newUsed[j] {
+					continue
+				}
+				if sim := similarity(oldItem, newItem); sim >= best {
+					bestOld, bestNew, best = i, j, sim
+				}
+			}
+		}
+		if bestOld < 0 {
+			break
+		}
+		oldUsed[bestOld], newUsed[bestNew] = true, true
+		diff.Changed = append(diff.Changed, models.ItemChange{From: oldItems[bestOld], To: newItems[bestNew]})
+	}
+
+	for i, item := range oldItems {
+		if !oldUsed[i] {
+			diff.Removed = append(diff.Removed, item)
+		}
+	}
+	for j, item := range newItems {
+		if !newUsed[j] {
+			diff.Added = append(diff.Added, item)
+		}
+	}
+	return diff
+}
+
+// similarity is the Dice coefficient of the character bigrams of two items, from 0 to 1
+func similarity(a, b string) float64 {
+	aBigrams, bBigrams := bigrams(taskKey(a)), bigrams(taskKey(b))
+	total := 0
+	for _, n := range aBigrams {
+		total += n
+	}
+	for _, n := range bBigrams {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	shared := 0
+	for bigram, n := range aBigrams {
+		shared += min(n, bBigrams[bigram])
+	}
+	return float64(2*shared) / float64(total)
+}
+
+func bigrams(s string) map[string]int {
+	runes := []rune(s)
+	counts := make(map[string]int)
+	for i := 0; i+1 < len(runes); i++ {
+		counts[string(runes[i:i+2])]++
+	}
+	return counts
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+
+	"meetingagent/models"
+)
+
+func TestDiffList(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new []string
+		want     models.ListDiff
+	}{
+		{
+			name: "both empty",
+			want: models.ListDiff{Added: []string{}, Removed: []string{}, Changed: []models.ItemChange{}},
+		},
+		{
+			name: "case, spacing and punctuation are unchanged",
+			old:  []string{"Finish the prototype.", "写周报"},
+			new:  []string{"写周报！", "finish  the prototype"},
+			want: models.ListDiff{Added: []string{}, Removed: []string{}, Changed: []models.ItemChange{}},
+		},
+		{
+			name: "added and removed",
+			old:  []string{"准备演示材料"},
+			new:  []string{"联系供应商"},
+			want: models.ListDiff{Added: []string{"联系供应商"}, Removed: []string{"准备演示材料"}, Changed: []models.ItemChange{}},
+		},
+		{
+			name: "reworded item is changed",
+			old:  []string{"Andy 下周五前完成原型开发", "订会议室"},
+			new:  []string{"Andy 下周三前完成原型开发", "发会议纪要"},
+			want: models.ListDiff{
+				Added:   []string{"发会议纪要"},
+				Removed: []string{"订会议室"},
+				Changed: []models.ItemChange{{From: "Andy 下周五前完成原型开发", To: "Andy 下周三前完成原型开发"}},
+			},
+		},
+		{
+			name: "duplicates match once",
+			old:  []string{"写周报", "写周报"},
+			new:  []string{"写周报"},
+			want: models.ListDiff{Added: []string{}, Removed: []string{"写周报"}, Changed: []models.ItemChange{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffList(tt.old, tt.new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffList() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
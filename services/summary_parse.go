@@ -0,0 +1,140 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"meetingagent/models"
+)
+
+// codeFence matches a fenced block such as ```json ... ``` with any language tag and case
+var codeFence = regexp.MustCompile("(?s)```[A-Za-z0-9_-]*[ \t]*\r?\n?(.*?)```")
+
+// extractJSONObject finds the JSON object in a model reply, looking inside the
+// first code fence if there is one and ignoring any text around the object
+func extractJSONObject(content string) (string, error) {
+	content = strings.TrimPrefix(strings.TrimSpace(content), "\ufeff")
+	if m := codeFence.FindStringSubmatch(content); m != nil && strings.Contains(m[1], "{") {
+		content = m[1]
+	}
+
+	start := strings.IndexByte(content, '{')
+	if start < 0 {
+		return "", errors.New("no JSON object found in the reply")
+	}
+	depth, inString, escaped := 0, false, false
+	for i := start; i < len(content); i++ {
+		ch := content[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if ch == '\\' {
+				escaped = true
+			} else if ch == '"' {
+				inString = false
+			}
+		case ch == '"':
+			inString = true
+		case ch == '{':
+			depth++
+		case ch == '}':
+			depth--
+			if depth == 0 {
+				return content[start : i+1], nil
+			}
+		}
+	}
+	return "", errors.New("the JSON object in the reply is not closed")
+}
+
+// removeTrailingCommas drops commas directly before a closing bracket or brace, outside of strings
+func removeTrailingCommas(s string) string {
+	var sb strings.Builder
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString:
+			if ch == '\\' {
+				escaped = true
+			} else if ch == '"' {
+				inString = false
+			}
+		case ch == '"':
+			inString = true
+		case ch == ',':
+			j := i + 1
+			for j < len(s) && strings.IndexByte(" \t\r\n", s[j]) >= 0 {
+				j++
+			}
+			if j < len(s) && (s[j] == '}' || s[j] == ']') {
+				continue
+			}
+		}
+		sb.WriteByte(ch)
+	}
+	return sb.String()
+}
+
+// parseSummaryResponse extracts, repairs and validates the SummaryResponse in a model reply.
+// The error describes what is wrong so that it can be sent back to the model.
+func parseSummaryResponse(content string) (*models.SummaryResponse, error) {
+	object, err := extractJSONObject(content)
+	if err != nil {
+		return nil, err
+	}
+	object = removeTrailingCommas(object)
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(object), &fields); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	var summaryResponse models.SummaryResponse
+	raw, ok := fields["summary"]
+	if !ok {
+		return nil, errors.New(`missing field "summary"`)
+	}
+	if err := json.Unmarshal(raw, &summaryResponse.Summary); err != nil {
+		return nil, errors.New(`field "summary" must be a string`)
+	}
+	if strings.TrimSpace(summaryResponse.Summary) == "" {
+		return nil, errors.New(`field "summary" is empty`)
+	}
+
+	raw, ok = fields["tasks"]
+	if !ok {
+		return nil, errors.New(`missing field "tasks"`)
+	}
+	if string(raw) != "null" {
+		if err := json.Unmarshal(raw, &summaryResponse.Tasks); err != nil {
+			return nil, errors.New(`field "tasks" must be an array of strings`)
+		}
+	}
+	if summaryResponse.Tasks == nil {
+		summaryResponse.Tasks = []string{}
+	}
+
+	// The sections are optional so that replies in the original format stay valid
+	sections := map[string]*[]string{
+		"decisions":      &summaryResponse.Decisions,
+		"open_questions": &summaryResponse.OpenQuestions,
+		"risks":          &summaryResponse.Risks,
+		"topics":         &summaryResponse.Topics,
+	}
+	for name, list := range sections {
+		if raw, ok := fields[name]; ok && string(raw) != "null" {
+			if err := json.Unmarshal(raw, list); err != nil {
+				return nil, fmt.Errorf("field %q must be an array of strings", name)
+			}
+		}
+	}
+	summaryResponse.SummarySections = summaryResponse.SummarySections.NonNil()
+	return &summaryResponse, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"meetingagent/models"
+)
+
+func TestParseSummaryResponse(t *testing.T) {
+	empty := models.SummarySections{}.NonNil()
+	tests := []struct {
+		name    string
+		content string
+		want    *models.SummaryResponse
+		err     string // Substring of the expected error; empty for success
+	}{
+		{
+			name:    "plain object",
+			content: `{"summary": "总结", "tasks": ["任务一"]}`,
+			want:    &models.SummaryResponse{Summary: "总结", Tasks: []string{"任务一"}, SummarySections: empty},
+		},
+		{
+			name:    "code fence with text around it",
+			content: "好的，以下是总结：\n```JSON\n{\"summary\": \"总结 {见附件}\", \"tasks\": [],}\n```\n希望有帮助",
+			want:    &models.SummaryResponse{Summary: "总结 {见附件}", Tasks: []string{}, SummarySections: empty},
+		},
+		{
+			name:    "null tasks",
+			content: `{"summary": "s", "tasks": null}`,
+			want:    &models.SummaryResponse{Summary: "s", Tasks: []string{}, SummarySections: empty},
+		},
+		{
+			name:    "no object",
+			content: "抱歉，我无法完成",
+			err:     "no JSON object found",
+		},
+		{
+			name:    "unclosed object",
+			content: `{"summary": "s", "tasks": [`,
+			err:     "not closed",
+		},
+		{
+			name:    "missing summary",
+			content: `{"tasks": []}`,
+			err:     `missing field "summary"`,
+		},
+		{
+			name:    "empty summary",
+			content: `{"summary": "  ", "tasks": []}`,
+			err:     `field "summary" is empty`,
+		},
+		{
+			name:    "missing tasks",
+			content: `{"summary": "s"}`,
+			err:     `missing field "tasks"`,
+		},
+		{
+			name:    "tasks of the wrong type",
+			content: `{"summary": "s", "tasks": "任务一"}`,
+			err:     `field "tasks" must be an array of strings`,
+		},
+		{
+			name:    "sections and null tasks",
+			content: `{"summary": "s", "tasks": null, "decisions": ["采用方案 A"], "risks": ["人手不足",], "topics": null}`,
+			want: &models.SummaryResponse{Summary: "s", Tasks: []string{}, SummarySections: models.SummarySections{
+				Decisions: []string{"采用方案 A"}, OpenQuestions: []string{}, Risks: []string{"人手不足"}, Topics: []string{},
+			}},
+		},
+		{
+			name:    "section of the wrong type",
+			content: `{"summary": "s", "tasks": [], "open_questions": [1]}`,
+			err:     `field "open_questions" must be an array of strings`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSummaryResponse(tt.content)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseSummaryResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
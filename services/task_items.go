@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"meetingagent/models"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// taskItemJSONFormat is the reply format requested when extracting the details of tasks
+const taskItemJSONFormat = `{"tasks": [{"title": "...", "assignee": "...", "due_text": "...", "due_date": "YYYY-MM-DD", "priority": "medium"}]}`
+
+// taskItemMessage is the system message for extracting the details of a summary's tasks
+const taskItemMessage = `你将收到会议信息、人员名单和会议总结中的待办事项（每行以 [序号] 开头）。
+请按顺序为每一项待办事项输出一个对象，对象数量与待办事项数量相同。
+title 为去掉负责人和截止时间后的简短任务描述；assignee 为负责人，必须是人员名单中的一个名字，无法确定时留空；
+due_text 为会议中提到的截止时间原文，due_date 为根据会议时间推算出的截止日期，格式为 YYYY-MM-DD，不能早于会议日期，没有截止时间时两者都留空；
+priority 为优先级，取 high、medium、low 之一。
+只输出一个 JSON 对象，格式为 ` + taskItemJSONFormat + `。`
+
+// GetTaskItems extracts the title, assignee, due date and priority of each task of a
+// summary. Assignees are matched to the meeting's speakers and participants, with
+// speakers linked to a participant stored under the participant's name, and due dates
+// are resolved against the meeting date. It also returns the tokens spent on all model
+// requests.
+func GetTaskItems(ctx context.Context, meeting *models.Meeting, utterances []models.Utterance, linked map[string]string, tasks []string) ([]models.TaskItem, models.TokenUsage, error) {
+	var usage models.TokenUsage
+	if len(tasks) == 0 {
+		return nil, usage, nil
+	}
+	if SummaryChatModel == nil {
+		return nil, usage, fmt.Errorf("summary chat model not initialized")
+	}
+
+	// The model sees redacted names, which are mapped back to the people they stand for
+	redactor := NewMeetingRedactor(meeting)
+	people := make(map[string]string)
+	assignees := assigneeNames(meeting, utterances, linked)
+	names := make([]string, 0, len(assignees))
+	for name, assignee := range assignees {
+		redacted := redactor.Redact(name)
+		people[strings.ToLower(redacted)] = assignee
+		names = append(names, redacted)
+	}
+	sort.Strings(names)
+	meetingDate := meeting.MeetingTime().Format("2006-01-02")
+	lines := make([]string, len(tasks))
+	for i, task := range tasks {
+		lines[i] = fmt.Sprintf("[%d] %s", i, redactor.Redact(task))
+	}
+
+	messages := []*schema.Message{
+		{
+			Role:    schema.System,
+			Content: taskItemMessage,
+		},
+		{
+			Role:    schema.User,
+			Content: redactor.Redact(FormatMeetingInfo(meeting)),
+		},
+		{
+			Role:    schema.User,
+			Content: "人员名单：" + strings.Join(names, ", "),
+		},
+		{
+			Role:    schema.User,
+			Content: "待办事项：\n" + strings.Join(lines, "\n"),
+		},
+	}
+	var items []models.TaskItem
+	err := generateJSON(ctx, &usage, messages, taskItemJSONFormat, func(content string) (err error) {
+		items, err = parseTaskItems(content, len(tasks), people, meetingDate)
+		return err
+	})
+	if err != nil {
+		return nil, usage, fmt.Errorf("failed to generate task items: %w", err)
+	}
+
+	if RestoreOutput() {
+		for i := range items {
+			items[i].Title = redactor.Restore(items[i].Title)
+			items[i].DueText = redactor.Restore(items[i].DueText)
+		}
+	}
+	return items, usage, nil
+}
+
+// assigneeNames maps the names a task may be assigned to, the speaker names of the
+// utterances and the meeting's participants, to the name stored as its assignee.
+// Speakers linked to a participant, as in linked, are stored under the participant's
+// name, which can be assigned as well.
+func assigneeNames(meeting *models.Meeting, utterances []models.Utterance, linked map[string]string) map[string]string {
+	seen := make(map[string]bool)
+	names := make(map[string]string)
+	add := func(name, assignee string) {
+		name = strings.TrimSpace(name)
+		if key := strings.ToLower(name); name != "" && !seen[key] {
+			seen[key] = true
+			names[name] = strings.TrimSpace(assignee)
+		}
+	}
+	for _, u := range utterances {
+		if participant, ok := linked[u.Speaker]; ok {
+			add(participant, participant)
+			add(u.Speaker, participant)
+		} else {
+			add(u.Speaker, u.Speaker)
+		}
+	}
+	for _, participant := range meeting.Participants() {
+		add(participant, participant)
+	}
+	return names
+}
+
+// parseTaskItems extracts and validates the task items in a model reply for n tasks.
+// Assignees are looked up in people, which maps lower-cased names as the model sees
+// them to the names stored. Due dates before meetingDate, as YYYY-MM-DD, are rejected.
+// The error describes what is wrong so that it can be sent back to the model.
+func parseTaskItems(content string, n int, people map[string]string, meetingDate string) ([]models.TaskItem, error) {
+	object, err := extractJSONObject(content)
+	if err != nil {
+		return nil, err
+	}
+	var reply struct {
+		Tasks []models.TaskItem `json:"tasks"`
+	}
+	if err := json.Unmarshal([]byte(removeTrailingCommas(object)), &reply); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	if len(reply.Tasks) != n {
+		return nil, fmt.Errorf(`field "tasks" must have %d items, one per task, but has %d`, n, len(reply.Tasks))
+	}
+	for i := range reply.Tasks {
+		item := &reply.Tasks[i]
+		item.Title = strings.TrimSpace(item.Title)
+		if item.Title == "" {
+			return nil, fmt.Errorf("task %d has no title", i)
+		}
+
+		if assignee := strings.TrimSpace(item.Assignee); assignee != "" {
+			name, ok := people[strings.ToLower(assignee)]
+			if !ok {
+				return nil, fmt.Errorf("assignee %q of task %d is not in the list of people; leave it empty if unsure", assignee, i)
+			}
+			item.Assignee = name
+		}
+
+		item.DueText = strings.TrimSpace(item.DueText)
+		item.DueDate = strings.TrimSpace(item.DueDate)
+		if item.DueDate != "" {
+			if _, err := time.Parse("2006-01-02", item.DueDate); err != nil {
+				return nil, fmt.Errorf("due_date %q of task %d is not a YYYY-MM-DD date", item.DueDate, i)
+			}
+			// Dates in the same format compare as strings
+			if item.DueDate < meetingDate {
+				return nil, fmt.Errorf("due_date %q of task %d is before the meeting date %s", item.DueDate, i, meetingDate)
+			}
+		}
+
+		switch item.Priority = strings.ToLower(strings.TrimSpace(item.Priority)); item.Priority {
+		case models.PriorityHigh, models.PriorityMedium, models.PriorityLow:
+		case "":
+			item.Priority = models.PriorityMedium
+		default:
+			return nil, fmt.Errorf("priority %q of task %d must be high, medium or low", item.Priority, i)
+		}
+	}
+	return reply.Tasks, nil
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"database/sql"
+	"reflect"
+	"strings"
+	"testing"
+
+	"meetingagent/models"
+)
+
+func TestParseTaskItems(t *testing.T) {
+	people := map[string]string{"lily": "Lily Chen", "lily chen": "Lily Chen", "andy": "Andy"}
+	tests := []struct {
+		name    string
+		content string
+		n       int
+		want    []models.TaskItem
+		err     string // Substring of the expected error; empty for success
+	}{
+		{
+			name: "assignee, due date and default priority",
+			content: "```json\n" + `{"tasks": [
+				{"title": " 完成原型 ", "assignee": "lily", "due_text": "下周五", "due_date": "2025-04-25", "priority": "HIGH"},
+				{"title": "订会议室", "assignee": "", "due_text": "", "due_date": "", "priority": ""},
+			]}` + "\n```",
+			n: 2,
+			want: []models.TaskItem{
+				{Title: "完成原型", Assignee: "Lily Chen", DueText: "下周五", DueDate: "2025-04-25", Priority: models.PriorityHigh},
+				{Title: "订会议室", Priority: models.PriorityMedium},
+			},
+		},
+		{
+			name:    "due on the meeting date",
+			content: `{"tasks": [{"title": "发纪要", "assignee": "Andy", "due_date": "2025-04-18", "priority": "low"}]}`,
+			n:       1,
+			want:    []models.TaskItem{{Title: "发纪要", Assignee: "Andy", DueDate: "2025-04-18", Priority: models.PriorityLow}},
+		},
+		{
+			name:    "wrong number of items",
+			content: `{"tasks": []}`,
+			n:       1,
+			err:     `must have 1 items`,
+		},
+		{
+			name:    "no title",
+			content: `{"tasks": [{"title": " "}]}`,
+			n:       1,
+			err:     "task 0 has no title",
+		},
+		{
+			name:    "unknown assignee",
+			content: `{"tasks": [{"title": "a", "assignee": "Tom"}]}`,
+			n:       1,
+			err:     `assignee "Tom" of task 0 is not in the list of people`,
+		},
+		{
+			name:    "malformed due date",
+			content: `{"tasks": [{"title": "a", "due_date": "4月25日"}]}`,
+			n:       1,
+			err:     "is not a YYYY-MM-DD date",
+		},
+		{
+			name:    "due date before the meeting",
+			content: `{"tasks": [{"title": "a", "due_date": "2025-04-11"}]}`,
+			n:       1,
+			err:     "is before the meeting date 2025-04-18",
+		},
+		{
+			name:    "unknown priority",
+			content: `{"tasks": [{"title": "a", "priority": "urgent"}]}`,
+			n:       1,
+			err:     "must be high, medium or low",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTaskItems(tt.content, tt.n, people, "2025-04-18")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseTaskItems() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssigneeNames(t *testing.T) {
+	meeting := &models.Meeting{ParticipantsJSON: sql.NullString{String: `["Tom", "andy"]`, Valid: true}}
+	utterances := []models.Utterance{{Speaker: "Lily"}, {Speaker: "Andy "}, {Speaker: "Speaker 1"}, {Speaker: ""}}
+	tests := []struct {
+		name   string
+		linked map[string]string
+		want   map[string]string
+	}{
+		{
+			name: "no links",
+			want: map[string]string{"Lily": "Lily", "Andy": "Andy", "Speaker 1": "Speaker 1", "Tom": "Tom"},
+		},
+		{
+			name:   "linked speakers are stored under the participant",
+			linked: map[string]string{"Lily": "Lily Chen", "Speaker 1": "Tom"},
+			want:   map[string]string{"Lily Chen": "Lily Chen", "Lily": "Lily Chen", "Andy": "Andy", "Tom": "Tom", "Speaker 1": "Tom"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := assigneeNames(meeting, utterances, tt.linked); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("assigneeNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,13 +2,10 @@ package services
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"meetingagent/config"
 	"meetingagent/models"
-
-	"github.com/cloudwego/eino/components/model"
-	"github.com/cloudwego/eino/schema"
+	"strings"
 )
 
 
@@ -19,40 +16,81 @@ type TaskAction struct {
 	Status    string `json:"status"`
 }
 
-// GetMeetingSummary generates a summary for a meeting given its transcript
-func GetMeetingSummary(ctx context.Context, transcript string) (*models.SummaryResponse, error) {
-	if SummaryChatModel == nil {
-		return nil, fmt.Errorf("summary chat model not initialized")
+// FormatMeetingInfo renders a meeting's metadata as prompt context
+func FormatMeetingInfo(meeting *models.Meeting) string {
+	var sb strings.Builder
+	sb.WriteString("会议信息：\n")
+	if meeting.Title.Valid && meeting.Title.String != "" {
+		sb.WriteString("标题：" + meeting.Title.String + "\n")
+	} else {
+		sb.WriteString("名称：" + meeting.Name + "\n")
+	}
+	sb.WriteString("时间：" + meeting.MeetingTime().Format("2006-01-02 15:04 (Monday)") + "\n")
+	if participants := meeting.Participants(); len(participants) > 0 {
+		sb.WriteString("参会人：" + strings.Join(participants, ", ") + "\n")
+	}
+	if meeting.Description.Valid && meeting.Description.String != "" {
+		sb.WriteString("描述：" + meeting.Description.String + "\n")
 	}
+	if meeting.Live {
+		sb.WriteString("状态：会议仍在进行中，以下记录截至目前\n")
+	}
+	return sb.String()
+}
 
-	// Prepare messages for the LLM
-	messages := []*schema.Message{
-		config.AppConfig.GetSummarySystemMessage(),
-		{
-			Role:    schema.User,
-			Content: transcript,
-		},
+// GetMeetingSummary generates a summary for a meeting from its transcript and metadata,
+// using the template and instructions of the meeting's summary options. It also returns
+// the tokens spent on all model requests.
+// Transcripts over the summary model's token budget are split into chunks on utterance
+// boundaries, which are summarized one by one and then merged. The transcript ranges
+// each part of the summary is based on are returned as its citations.
+func GetMeetingSummary(ctx context.Context, meeting *models.Meeting, utterances []models.Utterance) (*models.SummaryResponse, models.TokenUsage, error) {
+	if SummaryChatModel == nil {
+		return nil, models.TokenUsage{}, fmt.Errorf("summary chat model not initialized")
 	}
 
-	// Generate summary
-	response, err := SummaryChatModel.Generate(ctx, messages, model.WithTemperature(0.8))
+	// Replace personal information with placeholders before it leaves the server
+	redactor := NewMeetingRedactor(meeting)
+	// Utterances are numbered so that the model can cite them
+	lineUtterances := summaryUtterances(meeting, utterances)
+	lines := numberedLines(lineUtterances)
+	if len(lines) == 0 {
+		lines = strings.Split(meeting.Transcript.String, "\n")
+	}
+	prompt, err := newSummaryPrompt(meeting.SummaryOptions(), redactor.Redact(FormatMeetingInfo(meeting)), redactor, len(lineUtterances) > 0)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate summary: %v", err)
+		return nil, models.TokenUsage{}, err
 	}
 
-	// Process response content
-	if len(response.Content) > 0 {
-		if response.Content[0] == '`' && response.Content[len(response.Content)-1] == '`' {
-			response.Content = response.Content[7 : len(response.Content)-3]
-		}
+	var summaryResponse *models.SummaryResponse
+	budget := config.AppConfig.SummaryTokenBudget()
+	if transcriptText := strings.Join(lines, "\n"); EstimateTokens(transcriptText) <= budget {
+		summaryResponse, err = summarizeTranscript(ctx, prompt, redactor.Redact(transcriptText))
+	} else {
+		chunks := chunkLines(lines, budget)
+		summaryResponse, err = mapReduceSummary(ctx, redactor, prompt, chunks, budget)
 	}
-
-	// Parse the JSON response
-	var summaryResponse models.SummaryResponse
-	err = json.Unmarshal([]byte(response.Content), &summaryResponse)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse summary response: %v", err)
+		return nil, prompt.usage, err
+	}
+
+	if len(lineUtterances) > 0 {
+		summaryResponse.Citations = extractCitations(summaryResponse, lineUtterances)
+	}
+	if RestoreOutput() {
+		summaryResponse.Summary = redactor.Restore(summaryResponse.Summary)
+		for _, list := range [][]string{
+			summaryResponse.Tasks,
+			summaryResponse.Decisions,
+			summaryResponse.OpenQuestions,
+			summaryResponse.Risks,
+			summaryResponse.Topics,
+		} {
+			for i, item := range list {
+				list[i] = redactor.Restore(item)
+			}
+		}
 	}
 
-	return &summaryResponse, nil
+	return summaryResponse, prompt.usage, nil
 }
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"meetingagent/config"
+	"meetingagent/transcript"
+)
+
+// Transcriber turns a recorded audio file into a speaker-attributed transcript
+type Transcriber interface {
+	Transcribe(ctx context.Context, audioPath string) (*transcript.Document, error)
+}
+
+// DefaultTranscriber is used for audio uploads; nil when transcription is not configured
+var DefaultTranscriber Transcriber
+
+// SetTranscriber replaces the transcriber used for audio uploads, e.g. with a fake in tests
+func SetTranscriber(t Transcriber) {
+	DefaultTranscriber = t
+}
+
+// CommandTranscriber shells out to a local speech-to-text command. In Args,
+// "{input}" is replaced with the audio path and "{output}" with a temporary
+// file the command writes its transcript to; without "{output}" the
+// transcript is read from stdout. The transcript may be in any format the
+// transcript package understands, OutputFormat naming it when it can't be
+// detected from the content.
+type CommandTranscriber struct {
+	Command      string
+	Args         []string
+	OutputFormat string // File extension of the produced transcript, e.g. "vtt" or "json"
+	Timeout      time.Duration
+}
+
+// Transcribe runs the command on audioPath and parses its transcript
+func (t *CommandTranscriber) Transcribe(ctx context.Context, audioPath string) (*transcript.Document, error) {
+	if t.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	ext := "." + strings.TrimPrefix(t.OutputFormat, ".")
+	outputPath := ""
+	args := make([]string, len(t.Args))
+	for i, arg := range t.Args {
+		if strings.Contains(arg, "{output}") && outputPath == "" {
+			f, err := os.CreateTemp("", "transcript-*"+ext)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create transcript output file: %w", err)
+			}
+			outputPath = f.Name()
+			f.Close()
+			defer os.Remove(outputPath)
+		}
+		arg = strings.ReplaceAll(arg, "{input}", audioPath)
+		args[i] = strings.ReplaceAll(arg, "{output}", outputPath)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, t.Command, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("transcription command failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	output := stdout.Bytes()
+	if outputPath != "" {
+		var err error
+		output, err = os.ReadFile(outputPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read transcript output: %w", err)
+		}
+	}
+
+	doc, err := transcript.Parse(strings.TrimSuffix(filepath.Base(audioPath), filepath.Ext(audioPath))+ext, "", output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse transcription output: %w", err)
+	}
+	return doc, nil
+}
+
+// initTranscriber configures DefaultTranscriber from the transcription section of the config
+func initTranscriber() {
+	tc := config.AppConfig.Transcription
+	if tc.Command == "" {
+		return
+	}
+	DefaultTranscriber = &CommandTranscriber{
+		Command:      tc.Command,
+		Args:         tc.Args,
+		OutputFormat: tc.OutputFormat,
+		Timeout:      time.Duration(tc.TimeoutSeconds) * time.Second,
+	}
+	log.Printf("✔ Transcriber initialized: %s", tc.Command)
+}
@@ -230,7 +230,7 @@ async function loadMeetings() {
 
     meetingList.innerHTML = data.meetings.map(meeting => `
             <div class="meeting-item" data-id="${meeting.id}">
-                <div class="font-medium">${meeting.name}</div>
+                <div class="font-medium">${meeting.title && meeting.title.Valid ? meeting.title.String : meeting.name}</div>
                 <div class="text-sm text-gray-500">${new Date(meeting.uploaded_at).toLocaleDateString()}</div>
             </div>
         `).join('');
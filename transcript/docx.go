@@ -0,0 +1,114 @@
+package transcript
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"meetingagent/models"
+)
+
+// wordNamespace is the WordprocessingML namespace of the elements read from word/document.xml
+const wordNamespace = "http://schemas.openxmlformats.org/wordprocessingml/2006/main"
+
+// maxDocumentXMLSize bounds the uncompressed size of word/document.xml
+const maxDocumentXMLSize = 64 << 20
+
+// looksLikeDOCX reports whether data is a zip container holding a Word document
+func looksLikeDOCX(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04")) && bytes.Contains(data, []byte("word/document.xml"))
+}
+
+// parseDOCX extracts the paragraphs of a Word document. Paragraphs starting
+// with a "Name:" label become utterances of that speaker; others have no speaker.
+// The stored transcript text is the paragraphs joined by newlines.
+func parseDOCX(data []byte) (*Document, error) {
+	paragraphs, err := docxParagraphs(data)
+	if err != nil {
+		return nil, &ParseError{Format: FormatDOCX, Issues: []Issue{{Message: err.Error()}}}
+	}
+	if len(paragraphs) == 0 {
+		return nil, &ParseError{Format: FormatDOCX, Issues: []Issue{{Message: "document has no text"}}}
+	}
+
+	utterances := make([]models.Utterance, 0, len(paragraphs))
+	for i, p := range paragraphs {
+		u := models.Utterance{Seq: i, Text: p}
+		if m := speakerLabel.FindStringSubmatch(p); m != nil {
+			u.Speaker, u.Text = strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+		}
+		utterances = append(utterances, u)
+	}
+
+	return &Document{Format: FormatDOCX, Text: strings.Join(paragraphs, "\n") + "\n", Utterances: utterances}, nil
+}
+
+// docxParagraphs reads word/document.xml from the zip container and returns its non-blank paragraphs
+func docxParagraphs(data []byte) ([]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.New("not a valid .docx file: " + err.Error())
+	}
+	var document *zip.File
+	for _, f := range archive.File {
+		if f.Name == "word/document.xml" {
+			document = f
+			break
+		}
+	}
+	if document == nil {
+		return nil, errors.New("not a valid .docx file: word/document.xml is missing")
+	}
+	rc, err := document.Open()
+	if err != nil {
+		return nil, errors.New("failed to open word/document.xml: " + err.Error())
+	}
+	defer rc.Close()
+
+	var paragraphs []string
+	var current strings.Builder
+	inText := false
+	decoder := xml.NewDecoder(io.LimitReader(rc, maxDocumentXMLSize))
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.New("invalid word/document.xml: " + err.Error())
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab", "br", "cr":
+				current.WriteByte(' ')
+			}
+		case xml.EndElement:
+			if t.Name.Space != wordNamespace {
+				continue
+			}
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if p := strings.Join(strings.Fields(current.String()), " "); p != "" {
+					paragraphs = append(paragraphs, p)
+				}
+				current.Reset()
+			}
+		case xml.CharData:
+			if inText {
+				current.Write(t)
+			}
+		}
+	}
+	return paragraphs, nil
+}
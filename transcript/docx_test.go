@@ -0,0 +1,67 @@
+package transcript
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"meetingagent/models"
+)
+
+// docx builds a minimal Word document with one paragraph per entry
+func docx(t *testing.T, paragraphs ...string) []byte {
+	t.Helper()
+	var body strings.Builder
+	for _, p := range paragraphs {
+		body.WriteString(`<w:p><w:r><w:t>` + p + `</w:t></w:r></w:p>`)
+	}
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	f, err := archive.Create("word/document.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+		`<w:document xmlns:w="` + wordNamespace + `"><w:body>` + body.String() + `</w:body></w:document>`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseDOCX(t *testing.T) {
+	tests := []struct {
+		name       string
+		paragraphs []string
+		want       []models.Utterance
+		text       string
+		issues     int
+	}{
+		{
+			name:       "speaker labels",
+			paragraphs: []string{"Lily: 开始吧", "  ", "会议纪要", "Andy： 好的"},
+			want: []models.Utterance{
+				{Seq: 0, Speaker: "Lily", Text: "开始吧"},
+				{Seq: 1, Text: "会议纪要"},
+				{Seq: 2, Speaker: "Andy", Text: "好的"},
+			},
+			text: "Lily: 开始吧\n会议纪要\nAndy： 好的\n",
+		},
+		{
+			name:   "empty document",
+			issues: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse("", "", docx(t, tt.paragraphs...))
+			checkParse(t, doc, err, FormatDOCX, tt.want, tt.issues)
+			if err == nil && doc.Text != tt.text {
+				t.Errorf("Text = %q, want %q", doc.Text, tt.text)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package transcript
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"meetingagent/models"
+)
+
+// jsonTranscript mirrors the structured format documented in example/content.json
+type jsonTranscript struct {
+	Contents []jsonEntry `json:"contents"`
+}
+
+type jsonEntry struct {
+	TimeFrom string `json:"time_from"`
+	TimeTo   string `json:"time_to"`
+	User     string `json:"user"`
+	Content  struct {
+		Text string `json:"text"`
+	} `json:"content"`
+}
+
+func looksLikeJSON(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	return len(trimmed) > 0 && trimmed[0] == '{'
+}
+
+// parseJSON parses the structured JSON format. ok is false when data is not
+// that format; invalid JSON is only an error when strict is set.
+func parseJSON(data []byte, strict bool) (doc *Document, ok bool, err error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		if strict {
+			return nil, false, &ParseError{Format: FormatJSON, Issues: []Issue{{Message: "malformed JSON: " + err.Error()}}}
+		}
+		return nil, false, nil
+	}
+	if _, found := probe["contents"]; !found {
+		return nil, false, nil
+	}
+
+	var t jsonTranscript
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, false, &ParseError{Format: FormatJSON, Issues: []Issue{{Message: err.Error()}}}
+	}
+
+	var issues []Issue
+	utterances := make([]models.Utterance, 0, len(t.Contents))
+	for i, entry := range t.Contents {
+		entryNo := i + 1
+		start, err := parseClock(entry.TimeFrom)
+		if err != nil {
+			issues = append(issues, Issue{Entry: entryNo, Message: "time_from: " + err.Error()})
+			continue
+		}
+		end, err := parseClock(entry.TimeTo)
+		if err != nil {
+			issues = append(issues, Issue{Entry: entryNo, Message: "time_to: " + err.Error()})
+			continue
+		}
+		if end < start {
+			issues = append(issues, Issue{Entry: entryNo, Message: "time_to is before time_from"})
+			continue
+		}
+		speaker := strings.TrimSpace(entry.User)
+		if speaker == "" {
+			issues = append(issues, Issue{Entry: entryNo, Message: "user is empty"})
+			continue
+		}
+		text := strings.TrimSpace(entry.Content.Text)
+		if text == "" {
+			issues = append(issues, Issue{Entry: entryNo, Message: "content.text is empty"})
+			continue
+		}
+		utterances = append(utterances, models.Utterance{
+			Seq:     len(utterances),
+			Speaker: speaker,
+			StartMs: start,
+			EndMs:   end,
+			Text:    text,
+		})
+	}
+	if len(t.Contents) == 0 {
+		issues = append(issues, Issue{Message: "contents is empty"})
+	}
+	if len(issues) > 0 {
+		return nil, false, &ParseError{Format: FormatJSON, Issues: issues}
+	}
+
+	return &Document{Format: FormatJSON, Text: string(data), Utterances: utterances}, true, nil
+}
@@ -0,0 +1,136 @@
+package transcript
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"meetingagent/models"
+)
+
+// DefaultFillers are the filler words stripped when no list is configured
+var DefaultFillers = []string{"嗯", "呃", "额", "啊", "那个", "就是说", "然后呢", "um", "uh", "erm"}
+
+// fillerDelimiters are the characters that may surround a standalone filler word
+const fillerDelimiters = `\s,.!?;，。！？；、…`
+
+// NormalizeOptions selects the normalization steps applied by Normalize
+type NormalizeOptions struct {
+	MergeAdjacent bool                // Merge consecutive utterances of the same speaker
+	MaxGapMs      int64               // Longest pause bridged when merging; 0 means no limit
+	StripFillers  bool                // Remove standalone filler words
+	Fillers       []string            // DefaultFillers if empty
+	Aliases       map[string][]string // Canonical speaker name to alternative spellings
+}
+
+// Normalize returns a cleaned-up copy of utterances, leaving the input unchanged.
+// Speaker names are resolved through the alias table, and names that only differ
+// in case take the first spelling seen. Fillers are removed only where they stand
+// alone between punctuation, so "那个方案" is kept while "嗯，那个，我们" becomes "我们".
+// Each result lists the Seqs of the utterances it was built from.
+func Normalize(utterances []models.Utterance, opts NormalizeOptions) []models.Utterance {
+	resolve := speakerResolver(opts.Aliases)
+	var fillers *regexp.Regexp
+	if opts.StripFillers {
+		fillers = fillerPattern(opts.Fillers)
+	}
+
+	result := make([]models.Utterance, 0, len(utterances))
+	for _, u := range utterances {
+		speaker := resolve(u.Speaker)
+		text := strings.TrimSpace(u.Text)
+		if fillers != nil {
+			text = strings.TrimSpace(fillers.ReplaceAllString(text, "$1"))
+			text = strings.TrimLeft(text, "，,、；; ")
+		}
+		if text == "" {
+			continue
+		}
+
+		if n := len(result); opts.MergeAdjacent && n > 0 {
+			prev := &result[n-1]
+			if prev.Speaker == speaker && (opts.MaxGapMs <= 0 || u.StartMs-prev.EndMs <= opts.MaxGapMs) {
+				prev.Text = joinText(prev.Text, text)
+				if u.EndMs > prev.EndMs {
+					prev.EndMs = u.EndMs
+				}
+				prev.SourceSeqs = append(prev.SourceSeqs, u.Seq)
+				continue
+			}
+		}
+
+		result = append(result, models.Utterance{
+			MeetingID:  u.MeetingID,
+			Seq:        len(result),
+			Speaker:    speaker,
+			StartMs:    u.StartMs,
+			EndMs:      u.EndMs,
+			Text:       text,
+			SourceSeqs: []int{u.Seq},
+		})
+	}
+	return result
+}
+
+// speakerResolver maps speaker names to their canonical spelling
+func speakerResolver(aliases map[string][]string) func(string) string {
+	canonical := make(map[string]string)
+	for name, spellings := range aliases {
+		name = strings.TrimSpace(name)
+		canonical[strings.ToLower(name)] = name
+		for _, s := range spellings {
+			canonical[strings.ToLower(strings.TrimSpace(s))] = name
+		}
+	}
+	return func(speaker string) string {
+		speaker = strings.TrimSpace(speaker)
+		if speaker == "" {
+			return ""
+		}
+		key := strings.ToLower(speaker)
+		if name, ok := canonical[key]; ok {
+			return name
+		}
+		canonical[key] = speaker
+		return speaker
+	}
+}
+
+// fillerPattern matches runs of fillers together with the delimiter before them,
+// which is kept through the "$1" replacement, and the delimiters after them
+func fillerPattern(fillers []string) *regexp.Regexp {
+	if len(fillers) == 0 {
+		fillers = DefaultFillers
+	}
+	quoted := make([]string, 0, len(fillers))
+	for _, f := range fillers {
+		if f = strings.TrimSpace(f); f != "" {
+			quoted = append(quoted, regexp.QuoteMeta(f))
+		}
+	}
+	// Longer fillers first so "就是说" wins over a shorter prefix
+	sort.Slice(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	filler := `(?:` + strings.Join(quoted, "|") + `)`
+	return regexp.MustCompile(`(?i)(^|[` + fillerDelimiters + `]+)(?:` + filler + `[` + fillerDelimiters + `]*)*` + filler + `(?:[` + fillerDelimiters + `]+|$)`)
+}
+
+// joinText joins merged utterance texts. Chinese text is joined with a full-width
+// comma unless the first part already ends in punctuation, other text with a space.
+func joinText(a, b string) string {
+	last, _ := utf8.DecodeLastRuneInString(a)
+	first, _ := utf8.DecodeRuneInString(b)
+	switch {
+	case unicode.Is(unicode.Han, last):
+		return a + "，" + b
+	case isCJK(last) || isCJK(first):
+		return a + b
+	default:
+		return a + " " + b
+	}
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || (r >= 0x3000 && r <= 0x303f) || (r >= 0xff00 && r <= 0xffef)
+}
@@ -0,0 +1,72 @@
+package transcript
+
+import (
+	"reflect"
+	"testing"
+
+	"meetingagent/models"
+)
+
+func TestNormalize(t *testing.T) {
+	utterances := []models.Utterance{
+		{Seq: 0, Speaker: "Lily", StartMs: 0, EndMs: 1000, Text: "嗯，那个，我们开始"},
+		{Seq: 1, Speaker: "lily", StartMs: 1500, EndMs: 3000, Text: "先看那个方案"},
+		{Seq: 2, Speaker: "安迪", StartMs: 60000, EndMs: 61000, Text: "um, sure"},
+		{Seq: 3, Speaker: "Andy", StartMs: 61500, EndMs: 62000, Text: "嗯"},
+		{Seq: 4, Speaker: "Andy", StartMs: 63000, EndMs: 64000, Text: "let's go"},
+	}
+	tests := []struct {
+		name string
+		opts NormalizeOptions
+		want []models.Utterance
+	}{
+		{
+			name: "no steps keeps the text",
+			want: []models.Utterance{
+				{Seq: 0, Speaker: "Lily", StartMs: 0, EndMs: 1000, Text: "嗯，那个，我们开始", SourceSeqs: []int{0}},
+				{Seq: 1, Speaker: "Lily", StartMs: 1500, EndMs: 3000, Text: "先看那个方案", SourceSeqs: []int{1}},
+				{Seq: 2, Speaker: "安迪", StartMs: 60000, EndMs: 61000, Text: "um, sure", SourceSeqs: []int{2}},
+				{Seq: 3, Speaker: "Andy", StartMs: 61500, EndMs: 62000, Text: "嗯", SourceSeqs: []int{3}},
+				{Seq: 4, Speaker: "Andy", StartMs: 63000, EndMs: 64000, Text: "let's go", SourceSeqs: []int{4}},
+			},
+		},
+		{
+			name: "fillers and aliases",
+			opts: NormalizeOptions{StripFillers: true, Aliases: map[string][]string{"Andy": {"安迪"}}},
+			want: []models.Utterance{
+				{Seq: 0, Speaker: "Lily", StartMs: 0, EndMs: 1000, Text: "我们开始", SourceSeqs: []int{0}},
+				{Seq: 1, Speaker: "Lily", StartMs: 1500, EndMs: 3000, Text: "先看那个方案", SourceSeqs: []int{1}},
+				{Seq: 2, Speaker: "Andy", StartMs: 60000, EndMs: 61000, Text: "sure", SourceSeqs: []int{2}},
+				{Seq: 3, Speaker: "Andy", StartMs: 63000, EndMs: 64000, Text: "let's go", SourceSeqs: []int{4}},
+			},
+		},
+		{
+			name: "merge within the gap",
+			opts: NormalizeOptions{MergeAdjacent: true, MaxGapMs: 5000, StripFillers: true, Aliases: map[string][]string{"Andy": {"安迪"}}},
+			want: []models.Utterance{
+				{Seq: 0, Speaker: "Lily", StartMs: 0, EndMs: 3000, Text: "我们开始，先看那个方案", SourceSeqs: []int{0, 1}},
+				{Seq: 1, Speaker: "Andy", StartMs: 60000, EndMs: 64000, Text: "sure let's go", SourceSeqs: []int{2, 4}},
+			},
+		},
+		{
+			name: "merge without a gap limit",
+			opts: NormalizeOptions{MergeAdjacent: true},
+			want: []models.Utterance{
+				{Seq: 0, Speaker: "Lily", StartMs: 0, EndMs: 3000, Text: "嗯，那个，我们开始，先看那个方案", SourceSeqs: []int{0, 1}},
+				{Seq: 1, Speaker: "安迪", StartMs: 60000, EndMs: 61000, Text: "um, sure", SourceSeqs: []int{2}},
+				{Seq: 2, Speaker: "Andy", StartMs: 61500, EndMs: 64000, Text: "嗯，let's go", SourceSeqs: []int{3, 4}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Normalize(utterances, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+	if utterances[1].Speaker != "lily" || utterances[0].Text != "嗯，那个，我们开始" {
+		t.Error("Normalize changed its input")
+	}
+}
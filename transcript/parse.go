@@ -0,0 +1,141 @@
+// Package transcript recognises the supported transcript formats and turns
+// them into speaker-attributed utterances.
+package transcript
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"meetingagent/models"
+)
+
+// Format identifies the format a transcript was parsed from
+type Format string
+
+const (
+	// FormatRaw is used for content that matches no known format; it is stored as opaque text
+	FormatRaw  Format = "raw"
+	FormatJSON Format = "json"
+	// FormatText is the "hh:mm:ss-hh:mm:ss Speaker: text" line format of example/content.txt
+	FormatText Format = "text"
+	FormatVTT  Format = "vtt"
+	FormatSRT  Format = "srt"
+	// FormatDOCX is a Word document; its paragraphs become the transcript
+	FormatDOCX Format = "docx"
+)
+
+// Document is the result of parsing an uploaded transcript
+type Document struct {
+	Format     Format
+	Text       string // Transcript text stored on the meeting
+	Utterances []models.Utterance
+}
+
+// Issue describes a single problem found while validating a transcript
+type Issue struct {
+	Line    int    `json:"line,omitempty"`  // 1-based line number, for line oriented formats
+	Entry   int    `json:"entry,omitempty"` // 1-based entry index, for structured formats
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	switch {
+	case i.Line > 0:
+		return fmt.Sprintf("line %d: %s", i.Line, i.Message)
+	case i.Entry > 0:
+		return fmt.Sprintf("entry %d: %s", i.Entry, i.Message)
+	default:
+		return i.Message
+	}
+}
+
+// ParseError is returned when content is recognised as a known format but fails validation
+type ParseError struct {
+	Format Format
+	Issues []Issue
+}
+
+func (e *ParseError) Error() string {
+	msgs := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		msgs = append(msgs, issue.String())
+	}
+	return fmt.Sprintf("invalid %s transcript: %s", e.Format, strings.Join(msgs, "; "))
+}
+
+// Parse detects the format of data and parses it into a Document.
+// fileName and contentType are hints; either may be empty. A .txt file must be in the
+// timed text format.
+func Parse(fileName, contentType string, data []byte) (*Document, error) {
+	ext := strings.ToLower(filepath.Ext(fileName))
+	contentType = strings.ToLower(contentType)
+
+	switch {
+	case ext == ".docx" || strings.Contains(contentType, "wordprocessingml") || looksLikeDOCX(data):
+		return parseDOCX(data)
+	case ext == ".vtt" || strings.Contains(contentType, "text/vtt") || hasVTTHeader(data):
+		return parseVTT(data)
+	case ext == ".srt" || strings.Contains(contentType, "subrip") || looksLikeSRT(data):
+		return parseSRT(data)
+	}
+
+	if looksLikeJSON(data) || ext == ".json" || strings.Contains(contentType, "json") {
+		doc, ok, err := parseJSON(data, ext == ".json")
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return doc, nil
+		}
+	}
+
+	if ext == ".txt" || looksLikeTimedText(data) {
+		return parseTimedText(data)
+	}
+
+	return &Document{Format: FormatRaw, Text: string(data)}, nil
+}
+
+// FormatOffset renders a millisecond offset as hh:mm:ss
+func FormatOffset(ms int64) string {
+	secs := ms / 1000
+	return fmt.Sprintf("%02d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+}
+
+// parseClock parses hh:mm:ss or mm:ss timestamps with an optional
+// fractional part separated by '.' or ',' and returns milliseconds.
+func parseClock(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	frac := int64(0)
+	if i := strings.IndexAny(s, ".,"); i >= 0 {
+		digits := s[i+1:]
+		if digits == "" || len(digits) > 3 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		n, err := strconv.Atoi(digits)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		for j := len(digits); j < 3; j++ {
+			n *= 10
+		}
+		frac = int64(n)
+		s = s[:i]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, fmt.Errorf("invalid timestamp %q", s)
+	}
+	var total int64
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || (i > 0 && n > 59) {
+			return 0, fmt.Errorf("invalid timestamp %q", s)
+		}
+		total = total*60 + int64(n)
+	}
+	return total*1000 + frac, nil
+}
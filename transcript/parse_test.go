@@ -0,0 +1,163 @@
+package transcript
+
+import (
+	"errors"
+	"testing"
+
+	"meetingagent/models"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		fileName    string
+		contentType string
+		data        string
+		format      Format
+		want        []models.Utterance
+		issues      int // Number of issues in the expected ParseError; 0 for success
+	}{
+		{
+			name: "json",
+			data: `{"contents": [
+				{"time_from": "00:00:01", "time_to": "00:00:05", "user": "Lily", "content": {"text": " 开始吧 "}},
+				{"time_from": "00:01:00.5", "time_to": "00:01:02", "user": "Andy", "content": {"text": "好的"}}
+			]}`,
+			format: FormatJSON,
+			want: []models.Utterance{
+				{Seq: 0, Speaker: "Lily", StartMs: 1000, EndMs: 5000, Text: "开始吧"},
+				{Seq: 1, Speaker: "Andy", StartMs: 60500, EndMs: 62000, Text: "好的"},
+			},
+		},
+		{
+			name:   "json entries with errors",
+			data:   `{"contents": [{"time_from": "00:00:05", "time_to": "00:00:01", "user": "Lily", "content": {"text": "a"}}, {"time_from": "00:00:01", "time_to": "00:00:02", "user": "", "content": {"text": "b"}}]}`,
+			format: FormatJSON,
+			issues: 2,
+		},
+		{
+			name:     "malformed json with json extension",
+			fileName: "meeting.json",
+			data:     `{"contents": [`,
+			format:   FormatJSON,
+			issues:   1,
+		},
+		{
+			name:   "json without contents is raw",
+			data:   `{"title": "notes"}`,
+			format: FormatRaw,
+		},
+		{
+			name:   "timed text",
+			data:   "\ufeff00:00:00-00:00:45 Lily: 好的，开始\n\n00:00:46-00:01:30 Andy：确实\n",
+			format: FormatText,
+			want: []models.Utterance{
+				{Seq: 0, Speaker: "Lily", StartMs: 0, EndMs: 45000, Text: "好的，开始"},
+				{Seq: 1, Speaker: "Andy", StartMs: 46000, EndMs: 90000, Text: "确实"},
+			},
+		},
+		{
+			name:   "timed text with a bad line",
+			data:   "00:00:00-00:00:45 Lily: 好的\nnot a line\n00:00:50-00:00:46 Andy: 反了\n",
+			format: FormatText,
+			issues: 2,
+		},
+		{
+			name:   "timed text with a header",
+			data:   "产品评审会\n00:00:00-00:00:45 Lily: 好的\n00:00:46-00:01:30 Andy: 确实\n",
+			format: FormatText,
+			issues: 1,
+		},
+		{
+			name:   "timed text with a malformed first line",
+			data:   "00:00:00 Lily: 好的\n00:00:46-00:01:30 Andy: 确实\n00:01:31-00:02:15 Tom: 对\n",
+			format: FormatText,
+			issues: 1,
+		},
+		{
+			name:     "txt file that is not timed text",
+			fileName: "notes.txt",
+			data:     "Meeting notes\n00:00:46-00:01:30 Andy: 确实\n",
+			format:   FormatText,
+			issues:   1,
+		},
+		{
+			name: "vtt",
+			data: "WEBVTT\n\nNOTE a comment\n\n1\n00:00:01.000 --> 00:00:03.500\n<v Lily>Hello <b>there</b>\n\n" +
+				"00:00:04.000 --> 00:00:06.000\n<v Andy>Hi<v Tom>Hey\n",
+			format: FormatVTT,
+			want: []models.Utterance{
+				{Seq: 0, Speaker: "Lily", StartMs: 1000, EndMs: 3500, Text: "Hello there"},
+				{Seq: 1, Speaker: "Andy", StartMs: 4000, EndMs: 6000, Text: "Hi"},
+				{Seq: 2, Speaker: "Tom", StartMs: 4000, EndMs: 6000, Text: "Hey"},
+			},
+		},
+		{
+			name:     "vtt without header",
+			fileName: "captions.vtt",
+			data:     "00:00:01.000 --> 00:00:03.500\nHello\n",
+			format:   FormatVTT,
+			issues:   1,
+		},
+		{
+			name:   "srt",
+			data:   "1\n00:00:01,000 --> 00:00:02,000\nLily: 你好\n\n2\n00:00:02,500 --> 00:00:04,000\n没有说话人\n",
+			format: FormatSRT,
+			want: []models.Utterance{
+				{Seq: 0, Speaker: "Lily", StartMs: 1000, EndMs: 2000, Text: "你好"},
+				{Seq: 1, StartMs: 2500, EndMs: 4000, Text: "没有说话人"},
+			},
+		},
+		{
+			name:        "srt with a cue without text",
+			contentType: "application/x-subrip",
+			data:        "1\n00:00:01,000 --> 00:00:02,000\n",
+			format:      FormatSRT,
+			issues:      1,
+		},
+		{
+			name:   "plain text is raw",
+			data:   "Meeting notes\nNothing timed here",
+			format: FormatRaw,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := Parse(tt.fileName, tt.contentType, []byte(tt.data))
+			checkParse(t, doc, err, tt.format, tt.want, tt.issues)
+			if err == nil && doc.Text != tt.data {
+				t.Errorf("Text = %q, want the content unchanged", doc.Text)
+			}
+		})
+	}
+}
+
+// checkParse compares the result of Parse with the expected format and utterances,
+// or with a ParseError of the format with the given number of issues
+func checkParse(t *testing.T, doc *Document, err error, format Format, want []models.Utterance, issues int) {
+	t.Helper()
+	if issues > 0 {
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("err = %v, want a ParseError", err)
+		}
+		if parseErr.Format != format || len(parseErr.Issues) != issues {
+			t.Fatalf("err = %v, want %d %s issues", err, issues, format)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if doc.Format != format {
+		t.Errorf("Format = %q, want %q", doc.Format, format)
+	}
+	if len(doc.Utterances) != len(want) {
+		t.Fatalf("got %d utterances %+v, want %d", len(doc.Utterances), doc.Utterances, len(want))
+	}
+	for i, u := range doc.Utterances {
+		if w := want[i]; u.Seq != w.Seq || u.Speaker != w.Speaker || u.StartMs != w.StartMs || u.EndMs != w.EndMs || u.Text != w.Text {
+			t.Errorf("utterance %d = %+v, want %+v", i, u, w)
+		}
+	}
+}
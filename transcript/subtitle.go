@@ -0,0 +1,200 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"meetingagent/models"
+)
+
+var (
+	cueTiming    = regexp.MustCompile(`^(\S+)\s+-->\s+(\S+)`)
+	voiceTag     = regexp.MustCompile(`<v(?:\.[^\s>]*)?\s+([^>]+)>`)
+	markupTag    = regexp.MustCompile(`<[^>]*>`)
+	speakerLabel = regexp.MustCompile(`^([^:：<>]{1,40}?)\s*[:：]\s*(.+)$`)
+	srtIndex     = regexp.MustCompile(`^\d+$`)
+)
+
+// subtitleLine is a line of a subtitle file along with its 1-based line number
+type subtitleLine struct {
+	no   int
+	text string
+}
+
+// splitBlocks groups lines into blank-line separated blocks
+func splitBlocks(data []byte) [][]subtitleLine {
+	var blocks [][]subtitleLine
+	var current []subtitleLine
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		text := strings.TrimRight(scanner.Text(), " \t\r")
+		if lineNo == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if strings.TrimSpace(text) == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, subtitleLine{no: lineNo, text: text})
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
+
+func hasVTTHeader(data []byte) bool {
+	trimmed := bytes.TrimPrefix(bytes.TrimLeft(data, " \t\r\n"), []byte("\ufeff"))
+	return bytes.HasPrefix(trimmed, []byte("WEBVTT"))
+}
+
+// looksLikeSRT reports whether data starts with a numbered cue followed by a timing line
+func looksLikeSRT(data []byte) bool {
+	blocks := splitBlocks(data)
+	if len(blocks) == 0 || len(blocks[0]) < 2 {
+		return false
+	}
+	return srtIndex.MatchString(strings.TrimSpace(blocks[0][0].text)) && cueTiming.MatchString(blocks[0][1].text)
+}
+
+// parseVTT parses WebVTT captions; <v Speaker> voice tags become utterance speakers
+func parseVTT(data []byte) (*Document, error) {
+	blocks := splitBlocks(data)
+	if len(blocks) == 0 || !strings.HasPrefix(blocks[0][0].text, "WEBVTT") {
+		return nil, &ParseError{Format: FormatVTT, Issues: []Issue{{Line: 1, Message: `missing "WEBVTT" header`}}}
+	}
+
+	var issues []Issue
+	var utterances []models.Utterance
+	for _, block := range blocks[1:] {
+		first := block[0].text
+		if strings.HasPrefix(first, "NOTE") || strings.HasPrefix(first, "STYLE") || strings.HasPrefix(first, "REGION") {
+			continue
+		}
+		// The cue identifier line is optional
+		if !strings.Contains(first, "-->") {
+			block = block[1:]
+		}
+		cues, cueIssues := parseCue(block)
+		issues = append(issues, cueIssues...)
+		utterances = appendCues(utterances, cues)
+	}
+	if len(issues) > 0 {
+		return nil, &ParseError{Format: FormatVTT, Issues: issues}
+	}
+
+	return &Document{Format: FormatVTT, Text: string(data), Utterances: utterances}, nil
+}
+
+// parseSRT parses SubRip captions; a leading "Speaker:" label becomes the utterance speaker
+func parseSRT(data []byte) (*Document, error) {
+	var issues []Issue
+	var utterances []models.Utterance
+	for _, block := range splitBlocks(data) {
+		if srtIndex.MatchString(strings.TrimSpace(block[0].text)) {
+			block = block[1:]
+		}
+		cues, cueIssues := parseCue(block)
+		issues = append(issues, cueIssues...)
+		utterances = appendCues(utterances, cues)
+	}
+	if len(issues) > 0 {
+		return nil, &ParseError{Format: FormatSRT, Issues: issues}
+	}
+
+	return &Document{Format: FormatSRT, Text: string(data), Utterances: utterances}, nil
+}
+
+// parseCue parses a timing line followed by payload lines. A payload with
+// several voice tags yields one utterance per voice.
+func parseCue(block []subtitleLine) ([]models.Utterance, []Issue) {
+	if len(block) == 0 {
+		return nil, nil
+	}
+	timing := block[0]
+	m := cueTiming.FindStringSubmatch(timing.text)
+	if m == nil {
+		return nil, []Issue{{Line: timing.no, Message: `expected cue timing "start --> end"`}}
+	}
+	start, err := parseClock(m[1])
+	if err != nil {
+		return nil, []Issue{{Line: timing.no, Message: "start time: " + err.Error()}}
+	}
+	end, err := parseClock(m[2])
+	if err != nil {
+		return nil, []Issue{{Line: timing.no, Message: "end time: " + err.Error()}}
+	}
+	if end < start {
+		return nil, []Issue{{Line: timing.no, Message: "end time is before start time"}}
+	}
+	if len(block) == 1 {
+		return nil, []Issue{{Line: timing.no, Message: "cue has no text"}}
+	}
+
+	payload := make([]string, 0, len(block)-1)
+	for _, line := range block[1:] {
+		payload = append(payload, strings.TrimSpace(line.text))
+	}
+
+	var cues []models.Utterance
+	for _, part := range splitVoices(strings.Join(payload, "\n")) {
+		text := strings.Join(strings.Fields(markupTag.ReplaceAllString(part.text, "")), " ")
+		speaker := part.speaker
+		if speaker == "" {
+			if lm := speakerLabel.FindStringSubmatch(text); lm != nil {
+				speaker, text = strings.TrimSpace(lm[1]), strings.TrimSpace(lm[2])
+			}
+		}
+		if text == "" {
+			continue
+		}
+		cues = append(cues, models.Utterance{Speaker: speaker, StartMs: start, EndMs: end, Text: text})
+	}
+	return cues, nil
+}
+
+type voicePart struct {
+	speaker string
+	text    string
+}
+
+// splitVoices splits a cue payload on <v Speaker> tags
+func splitVoices(payload string) []voicePart {
+	locs := voiceTag.FindAllStringSubmatchIndex(payload, -1)
+	if len(locs) == 0 {
+		return []voicePart{{text: payload}}
+	}
+
+	var parts []voicePart
+	if lead := strings.TrimSpace(payload[:locs[0][0]]); lead != "" {
+		parts = append(parts, voicePart{text: lead})
+	}
+	for i, loc := range locs {
+		end := len(payload)
+		if i+1 < len(locs) {
+			end = locs[i+1][0]
+		}
+		parts = append(parts, voicePart{
+			speaker: strings.TrimSpace(payload[loc[2]:loc[3]]),
+			text:    payload[loc[1]:end],
+		})
+	}
+	return parts
+}
+
+func appendCues(utterances, cues []models.Utterance) []models.Utterance {
+	for _, cue := range cues {
+		cue.Seq = len(utterances)
+		utterances = append(utterances, cue)
+	}
+	return utterances
+}
@@ -0,0 +1,118 @@
+package transcript
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+
+	"meetingagent/models"
+)
+
+var (
+	// textLinePrefix detects the "hh:mm:ss-hh:mm:ss Speaker: text" format from its first line
+	textLinePrefix = regexp.MustCompile(`^\d{1,2}:\d{2}:\d{2}\s*-\s*\d{1,2}:\d{2}:\d{2}\s`)
+	// textLine captures start, end, speaker and text; both ASCII and full-width colons are accepted
+	textLine = regexp.MustCompile(`^(\S+?)\s*-\s*(\S+)\s+([^:：]+?)\s*[:：]\s*(.*)$`)
+)
+
+// looksLikeTimedText reports whether data is in the timed text format: its first
+// non-blank line is a timed text line, or most of its non-blank lines are. The latter
+// keeps a transcript with a header or a malformed first line from passing as opaque text.
+func looksLikeTimedText(data []byte) bool {
+	lines, timed := 0, 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(scanner.Text(), "\ufeff"))
+		if line == "" {
+			continue
+		}
+		matched := textLinePrefix.MatchString(line)
+		if lines == 0 && matched {
+			return true
+		}
+		lines++
+		if matched {
+			timed++
+		}
+	}
+	return timed*2 > lines
+}
+
+// parseTimedText parses the plain-text format used by example/content.txt.
+// Every non-blank line must be a complete utterance.
+func parseTimedText(data []byte) (*Document, error) {
+	var issues []Issue
+	var utterances []models.Utterance
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if lineNo == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		if line == "" {
+			continue
+		}
+
+		m := textLine.FindStringSubmatch(line)
+		if m == nil {
+			issues = append(issues, Issue{Line: lineNo, Message: `expected "hh:mm:ss-hh:mm:ss Speaker: text"`})
+			continue
+		}
+		start, err := parseClock(m[1])
+		if err != nil {
+			issues = append(issues, Issue{Line: lineNo, Message: "start time: " + err.Error()})
+			continue
+		}
+		end, err := parseClock(m[2])
+		if err != nil {
+			issues = append(issues, Issue{Line: lineNo, Message: "end time: " + err.Error()})
+			continue
+		}
+		if end < start {
+			issues = append(issues, Issue{Line: lineNo, Message: "end time is before start time"})
+			continue
+		}
+		text := strings.TrimSpace(m[4])
+		if text == "" {
+			issues = append(issues, Issue{Line: lineNo, Message: "text is empty"})
+			continue
+		}
+		utterances = append(utterances, models.Utterance{
+			Seq:     len(utterances),
+			Speaker: strings.TrimSpace(m[3]),
+			StartMs: start,
+			EndMs:   end,
+			Text:    text,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		issues = append(issues, Issue{Line: lineNo + 1, Message: err.Error()})
+	}
+	if len(issues) > 0 {
+		return nil, &ParseError{Format: FormatText, Issues: issues}
+	}
+
+	return &Document{Format: FormatText, Text: string(data), Utterances: utterances}, nil
+}
+
+// Render formats utterances in the "hh:mm:ss-hh:mm:ss Speaker: text" line format
+func Render(utterances []models.Utterance) string {
+	var sb strings.Builder
+	for _, u := range utterances {
+		sb.WriteString(FormatOffset(u.StartMs))
+		sb.WriteByte('-')
+		sb.WriteString(FormatOffset(u.EndMs))
+		sb.WriteByte(' ')
+		sb.WriteString(u.Speaker)
+		sb.WriteString(": ")
+		sb.WriteString(strings.ReplaceAll(u.Text, "\n", " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}